@@ -7,6 +7,9 @@ import (
 )
 
 func (c *Fosite) WriteAccessError(rw http.ResponseWriter, _ AccessRequester, err error) {
+	if nonce := DPoPNonceFromError(err); nonce != "" {
+		rw.Header().Set("DPoP-Nonce", nonce)
+	}
 	writeJsonError(rw, err)
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/ory/fosite"
 	. "github.com/ory/fosite/internal"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -42,6 +43,8 @@ func TestWriteAccessError_RFC6749(t *testing.T) {
 		{ErrInvalidScope, "invalid_scope"},
 		{ErrUnauthorizedClient, "unauthorized_client"},
 		{ErrUnsupportedGrantType, "unsupported_grant_type"},
+		{ErrInvalidDPoPProof, "invalid_dpop_proof"},
+		{ErrUseDPoPNonce, "use_dpop_nonce"},
 	} {
 		rw := httptest.NewRecorder()
 		f.WriteAccessError(rw, nil, c.err)
@@ -59,3 +62,13 @@ func TestWriteAccessError_RFC6749(t *testing.T) {
 		assert.Equal(t, c.err.Error(), params.Description, "(%d) %s: description", k, c.code)
 	}
 }
+
+func TestWriteAccessError_DPoPNonce(t *testing.T) {
+	f := &Fosite{}
+	rw := httptest.NewRecorder()
+	f.WriteAccessError(rw, nil, errors.WithStack(&DPoPNonceError{Nonce: "nonce"}))
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, "nonce", rw.Header().Get("DPoP-Nonce"))
+	assert.Contains(t, rw.Body.String(), "use_dpop_nonce")
+}
@@ -4,6 +4,9 @@ type AccessRequest struct {
 	GrantTypes       Arguments `json:"grantTypes" gorethink:"grantTypes"`
 	HandledGrantType Arguments `json:"handledGrantType" gorethink:"handledGrantType"`
 
+	// IntrospectedTokenType is the type of the token found by token introspection.
+	IntrospectedTokenType TokenType `json:"-" gorethink:"-"`
+
 	Request
 }
 
@@ -20,3 +23,11 @@ func NewAccessRequest(session Session) *AccessRequest {
 func (a *AccessRequest) GetGrantTypes() Arguments {
 	return a.GrantTypes
 }
+
+func (a *AccessRequest) GetIntrospectedTokenType() TokenType {
+	return a.IntrospectedTokenType
+}
+
+func (a *AccessRequest) SetIntrospectedTokenType(tokenType TokenType) {
+	a.IntrospectedTokenType = tokenType
+}
@@ -72,12 +72,46 @@ func (f *Fosite) NewAccessRequest(ctx context.Context, r *http.Request, session
 
 	if !client.IsPublic() {
 		// Enforce client authentication
-		if err := f.Hasher.Compare(client.GetHashedSecret(), []byte(clientSecret)); err != nil {
+		if err := checkClientSecretBasic(client); err != nil {
+			return accessRequest, err
+		} else if err := CompareClientSecret(f.Hasher, client, []byte(clientSecret)); err != nil {
 			return accessRequest, errors.Wrap(ErrInvalidClient, err.Error())
 		}
 	}
 	accessRequest.Client = client
 
+	// Grants continuing an authorization, e.g. authorization_code and refresh_token, use the scopes of the original
+	// request if the scope parameter is omitted.
+	if err := f.validateScopes(accessRequest, accessRequest.GetGrantTypes().Exact("client_credentials") || accessRequest.GetGrantTypes().Exact("password")); err != nil {
+		return accessRequest, err
+	}
+
+	audience, err := GetAudiences(r.PostForm)
+	if err != nil {
+		return accessRequest, err
+	}
+	accessRequest.SetRequestedAudience(audience)
+	if err := f.validateAudience(accessRequest); err != nil {
+		return accessRequest, err
+	}
+
+	details, err := GetAuthorizationDetails(r.PostForm)
+	if err != nil {
+		return accessRequest, err
+	}
+	accessRequest.SetRequestedAuthorizationDetails(details)
+	if err := f.validateAuthorizationDetails(ctx, accessRequest); err != nil {
+		return accessRequest, err
+	}
+
+	if f.DPoPStrategy != nil {
+		if proof, err := f.DPoPStrategy.ValidateRequestProof(ctx, r, ""); err != nil {
+			return accessRequest, err
+		} else if proof != nil {
+			accessRequest.SetDPoPJWKThumbprint(proof.JWKThumbprint)
+		}
+	}
+
 	var found bool = false
 	for _, loader := range f.TokenEndpointHandlers {
 		if err := loader.HandleTokenEndpointRequest(ctx, accessRequest); err == nil {
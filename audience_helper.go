@@ -0,0 +1,77 @@
+package fosite
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GetAudiences extracts the requested audiences from the request's form values. Audiences are requested using
+// the space-delimited "audience" parameter and the "resource" parameter as defined in
+// https://tools.ietf.org/html/rfc8707#section-2
+//
+//   resource
+//   Indicates the target service or resource to which access is being
+//   requested.  Its value MUST be an absolute URI, as specified by
+//   Section 4.3 of [RFC3986].  The URI MUST NOT include a fragment
+//   component.  [...] Multiple "resource" parameters MAY be used to
+//   indicate that the requested token is intended to be used at multiple
+//   resources.
+func GetAudiences(form url.Values) (Arguments, error) {
+	audiences := Arguments{}
+	for _, audience := range form["audience"] {
+		for _, a := range removeEmpty(strings.Split(audience, " ")) {
+			audiences = appendUnique(audiences, a)
+		}
+	}
+
+	for _, resource := range form["resource"] {
+		u, err := url.Parse(resource)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidTarget, "Resource %s is not a valid URI", resource)
+		} else if !u.IsAbs() {
+			return nil, errors.Wrapf(ErrInvalidTarget, "Resource %s must be an absolute URI", resource)
+		} else if u.Fragment != "" {
+			return nil, errors.Wrapf(ErrInvalidTarget, "Resource %s must not include a fragment component", resource)
+		}
+		audiences = appendUnique(audiences, resource)
+	}
+
+	return audiences, nil
+}
+
+// GetClientAudience returns the audiences the client is allowed to request, or nil if the client does not
+// implement ClientWithAudience.
+func GetClientAudience(c Client) Arguments {
+	if ac, ok := c.(ClientWithAudience); ok {
+		return ac.GetAudience()
+	}
+	return nil
+}
+
+func (f *Fosite) getAudienceMatchingStrategy() AudienceMatchingStrategy {
+	if f.AudienceMatchingStrategy == nil {
+		return DefaultAudienceMatchingStrategy
+	}
+	return f.AudienceMatchingStrategy
+}
+
+func (f *Fosite) validateAudience(request Requester) error {
+	allowed := GetClientAudience(request.GetClient())
+	for _, audience := range request.GetRequestedAudience() {
+		if !f.getAudienceMatchingStrategy()(allowed, audience) {
+			return errors.Wrapf(ErrInvalidTarget, "The client is not allowed to request audience %s", audience)
+		}
+	}
+	return nil
+}
+
+func appendUnique(args Arguments, arg string) Arguments {
+	for _, has := range args {
+		if has == arg {
+			return args
+		}
+	}
+	return append(args, arg)
+}
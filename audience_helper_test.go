@@ -0,0 +1,50 @@
+package fosite
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAudiences(t *testing.T) {
+	for k, c := range []struct {
+		form      url.Values
+		expect    Arguments
+		expectErr error
+	}{
+		{form: url.Values{}, expect: Arguments{}},
+		{form: url.Values{"audience": {"foo bar", "foo"}}, expect: Arguments{"foo", "bar"}},
+		{form: url.Values{"audience": {"foo"}, "resource": {"https://api.example.com/", "https://api.example.com/"}}, expect: Arguments{"foo", "https://api.example.com/"}},
+		{form: url.Values{"resource": {"/relative"}}, expectErr: ErrInvalidTarget},
+		{form: url.Values{"resource": {"https://api.example.com/#fragment"}}, expectErr: ErrInvalidTarget},
+		{form: url.Values{"resource": {"%gh&%ij"}}, expectErr: ErrInvalidTarget},
+	} {
+		audiences, err := GetAudiences(c.form)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s", k, err, c.expectErr)
+		if c.expectErr == nil {
+			assert.Equal(t, c.expect, audiences, "(%d)", k)
+		}
+	}
+}
+
+func TestValidateAudience(t *testing.T) {
+	f := &Fosite{}
+	r := NewRequest()
+	r.Client = &DefaultClient{Audience: []string{"https://api.example.com/payments"}}
+
+	r.SetRequestedAudience(Arguments{"https://api.example.com/payments/transfers"})
+	assert.Nil(t, f.validateAudience(r))
+
+	r.SetRequestedAudience(Arguments{"https://api.example.com/users"})
+	assert.Equal(t, ErrInvalidTarget, errors.Cause(f.validateAudience(r)))
+
+	f.AudienceMatchingStrategy = ExactAudienceMatchingStrategy
+	r.SetRequestedAudience(Arguments{"https://api.example.com/payments/transfers"})
+	assert.Equal(t, ErrInvalidTarget, errors.Cause(f.validateAudience(r)))
+
+	r.Client = &DefaultClient{}
+	r.SetRequestedAudience(Arguments{})
+	assert.Nil(t, f.validateAudience(r))
+}
@@ -0,0 +1,52 @@
+package fosite
+
+import (
+	"net/url"
+	"strings"
+)
+
+// AudienceMatchingStrategy is a strategy for matching a requested audience against the audiences a client is
+// allowed to request.
+type AudienceMatchingStrategy func(haystack []string, needle string) bool
+
+// ExactAudienceMatchingStrategy only matches audiences that are registered verbatim.
+func ExactAudienceMatchingStrategy(haystack []string, needle string) bool {
+	for _, this := range haystack {
+		if this == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultAudienceMatchingStrategy matches audiences that are registered verbatim and, for URLs, sub-paths of
+// registered audiences. For example, "https://api.example.com/payments" allows requesting
+// "https://api.example.com/payments/transfers" but neither "https://api.example.com/paymentsfoo" nor
+// "https://api.example.com/".
+func DefaultAudienceMatchingStrategy(haystack []string, needle string) bool {
+	if ExactAudienceMatchingStrategy(haystack, needle) {
+		return true
+	}
+
+	n, err := url.Parse(needle)
+	if err != nil || !n.IsAbs() {
+		return false
+	}
+
+	for _, this := range haystack {
+		h, err := url.Parse(this)
+		if err != nil || !h.IsAbs() {
+			continue
+		}
+
+		if h.Scheme != n.Scheme || h.Host != n.Host || h.RawQuery != n.RawQuery {
+			continue
+		}
+
+		if strings.HasPrefix(n.Path, strings.TrimRight(h.Path, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
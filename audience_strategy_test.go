@@ -0,0 +1,32 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultAudienceMatchingStrategy(t *testing.T) {
+	haystack := []string{"https://api.example.com/payments", "https://api.example.com/users/", "urn:example:api"}
+
+	for k, c := range []struct {
+		needle string
+		expect bool
+	}{
+		{needle: "https://api.example.com/payments", expect: true},
+		{needle: "https://api.example.com/payments/transfers", expect: true},
+		{needle: "https://api.example.com/users/1", expect: true},
+		{needle: "urn:example:api", expect: true},
+		{needle: "https://api.example.com/paymentsfoo", expect: false},
+		{needle: "https://api.example.com/", expect: false},
+		{needle: "http://api.example.com/payments/transfers", expect: false},
+		{needle: "https://evil.example.com/payments/transfers", expect: false},
+		{needle: "urn:example:api:foo", expect: false},
+		{needle: "/payments", expect: false},
+	} {
+		assert.Equal(t, c.expect, DefaultAudienceMatchingStrategy(haystack, c.needle), "(%d) %s", k, c.needle)
+	}
+
+	assert.True(t, ExactAudienceMatchingStrategy(haystack, "https://api.example.com/payments"))
+	assert.False(t, ExactAudienceMatchingStrategy(haystack, "https://api.example.com/payments/transfers"))
+}
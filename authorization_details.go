@@ -0,0 +1,99 @@
+package fosite
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// AuthorizationDetail is a single authorization details object as defined in
+// https://tools.ietf.org/html/rfc9396#section-2
+//
+//   Each JSON object contains the data to specify the authorization
+//   requirements for a certain type of resource.  The type of resource or
+//   access requirement is determined by the "type" field [...]
+//
+// Besides "type", an object may contain the common data fields "locations", "actions", "datatypes", "identifier"
+// and "privileges" as well as any field specific to its type.
+type AuthorizationDetail map[string]interface{}
+
+// GetType returns the detail's type, or an empty string if it is missing or not a string.
+func (d AuthorizationDetail) GetType() string {
+	t, _ := d["type"].(string)
+	return t
+}
+
+// Equals returns true if both details contain the same fields and values.
+func (d AuthorizationDetail) Equals(other AuthorizationDetail) bool {
+	return reflect.DeepEqual(d, other)
+}
+
+// AuthorizationDetails is a list of authorization details objects.
+type AuthorizationDetails []AuthorizationDetail
+
+// Has returns true if the list contains a detail equal to the given one.
+func (d AuthorizationDetails) Has(detail AuthorizationDetail) bool {
+	for _, this := range d {
+		if this.Equals(detail) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizationDetailValidator validates an authorization details object of a registered type. It returns an
+// error, usually wrapping ErrInvalidAuthorizationDetails, if the detail is malformed or if the client is not allowed
+// to request it.
+type AuthorizationDetailValidator func(ctx context.Context, client Client, detail AuthorizationDetail) error
+
+// AuthorizationDetailTypes is a registry of the authorization details types an authorization server supports,
+// mapping each type to its validator. A nil validator accepts any detail of that type.
+type AuthorizationDetailTypes map[string]AuthorizationDetailValidator
+
+// GetAuthorizationDetails parses the "authorization_details" parameter of the request's form values as defined in
+// https://tools.ietf.org/html/rfc9396#section-2
+//
+//   The request parameter "authorization_details" contains, in JSON
+//   notation, an array of objects.
+//
+// It is shared by all endpoints that accept authorization details, e.g. the authorize and the token endpoint.
+func GetAuthorizationDetails(form url.Values) (AuthorizationDetails, error) {
+	raw := form.Get("authorization_details")
+	if raw == "" {
+		return AuthorizationDetails{}, nil
+	}
+
+	var details AuthorizationDetails
+	if err := json.Unmarshal([]byte(raw), &details); err != nil {
+		return nil, errors.Wrapf(ErrInvalidAuthorizationDetails, "Parameter authorization_details must be a JSON array of objects: %s", err)
+	}
+
+	for k, detail := range details {
+		if detail == nil {
+			return nil, errors.Wrapf(ErrInvalidAuthorizationDetails, "Authorization details object %d must not be null", k)
+		} else if detail.GetType() == "" {
+			return nil, errors.Wrapf(ErrInvalidAuthorizationDetails, "Authorization details object %d is missing a type", k)
+		}
+	}
+
+	return details, nil
+}
+
+func (f *Fosite) validateAuthorizationDetails(ctx context.Context, request Requester) error {
+	for _, detail := range request.GetRequestedAuthorizationDetails() {
+		validator, ok := f.AuthorizationDetailTypes[detail.GetType()]
+		if !ok {
+			return errors.Wrapf(ErrInvalidAuthorizationDetails, "Authorization details type %s is not supported", detail.GetType())
+		} else if validator == nil {
+			continue
+		}
+
+		if err := validator(ctx, request.GetClient(), detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package fosite
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAuthorizationDetails(t *testing.T) {
+	for k, c := range []struct {
+		form      url.Values
+		expect    AuthorizationDetails
+		expectErr error
+	}{
+		{form: url.Values{}, expect: AuthorizationDetails{}},
+		{
+			form: url.Values{"authorization_details": {`[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"45.00"}}]`}},
+			expect: AuthorizationDetails{{
+				"type":             "payment_initiation",
+				"instructedAmount": map[string]interface{}{"currency": "EUR", "amount": "45.00"},
+			}},
+		},
+		{form: url.Values{"authorization_details": {`{"type":"payment_initiation"}`}}, expectErr: ErrInvalidAuthorizationDetails},
+		{form: url.Values{"authorization_details": {`[{"actions":["read"]}]`}}, expectErr: ErrInvalidAuthorizationDetails},
+		{form: url.Values{"authorization_details": {`[{"type":1}]`}}, expectErr: ErrInvalidAuthorizationDetails},
+		{form: url.Values{"authorization_details": {`[null]`}}, expectErr: ErrInvalidAuthorizationDetails},
+		{form: url.Values{"authorization_details": {`[`}}, expectErr: ErrInvalidAuthorizationDetails},
+	} {
+		details, err := GetAuthorizationDetails(c.form)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s", k, err, c.expectErr)
+		if c.expectErr == nil {
+			assert.Equal(t, c.expect, details, "(%d)", k)
+		}
+	}
+}
+
+func TestValidateAuthorizationDetails(t *testing.T) {
+	f := &Fosite{AuthorizationDetailTypes: AuthorizationDetailTypes{
+		"account_information": nil,
+		"payment_initiation": func(_ context.Context, _ Client, detail AuthorizationDetail) error {
+			if _, ok := detail["instructedAmount"]; !ok {
+				return errors.Wrap(ErrInvalidAuthorizationDetails, "instructedAmount is required")
+			}
+			return nil
+		},
+	}}
+
+	for k, c := range []struct {
+		details   AuthorizationDetails
+		expectErr error
+	}{
+		{details: AuthorizationDetails{}},
+		{details: AuthorizationDetails{{"type": "account_information"}}},
+		{details: AuthorizationDetails{{"type": "payment_initiation", "instructedAmount": "45.00"}}},
+		{details: AuthorizationDetails{{"type": "payment_initiation"}}, expectErr: ErrInvalidAuthorizationDetails},
+		{details: AuthorizationDetails{{"type": "unknown"}}, expectErr: ErrInvalidAuthorizationDetails},
+	} {
+		r := NewRequest()
+		r.SetRequestedAuthorizationDetails(c.details)
+		err := f.validateAuthorizationDetails(nil, r)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s", k, err, c.expectErr)
+	}
+}
+
+func TestAuthorizationDetailsHas(t *testing.T) {
+	details := AuthorizationDetails{{"type": "account_information", "actions": []interface{}{"read"}}}
+	assert.True(t, details.Has(AuthorizationDetail{"type": "account_information", "actions": []interface{}{"read"}}))
+	assert.False(t, details.Has(AuthorizationDetail{"type": "account_information", "actions": []interface{}{"write"}}))
+	assert.False(t, details.Has(AuthorizationDetail{"type": "account_information"}))
+}
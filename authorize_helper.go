@@ -1,10 +1,10 @@
 package fosite
 
 import (
+	"net"
 	"net/url"
 	"strings"
 
-	"github.com/asaskevich/govalidator"
 	"github.com/pkg/errors"
 )
 
@@ -56,15 +56,23 @@ func GetRedirectURIFromRequestValues(values url.Values) (string, error) {
 //     particular end-user authorization and validates this redirect URI
 //     with the redirect URI passed to the token's endpoint, such an
 //     attack is detected (see Section 5.2.4.5).
+//
+// Loopback IP redirect URIs match with any port, see LoopbackRedirectURIMatcher. Use MatchRedirectURI to match
+// using another RedirectURIMatcher.
 func MatchRedirectURIWithClientRedirectURIs(rawurl string, client Client) (*url.URL, error) {
+	return MatchRedirectURI(rawurl, client, LoopbackRedirectURIMatcher)
+}
+
+// MatchRedirectURI is MatchRedirectURIWithClientRedirectURIs using the given matcher.
+func MatchRedirectURI(rawurl string, client Client, matcher RedirectURIMatcher) (*url.URL, error) {
 	if rawurl == "" && len(client.GetRedirectURIs()) == 1 {
 		if redirectURIFromClient, err := url.Parse(client.GetRedirectURIs()[0]); err == nil && IsValidRedirectURI(redirectURIFromClient) {
 			// If no redirect_uri was given and the client has exactly one valid redirect_uri registered, use that instead
 			return redirectURIFromClient, nil
 		}
-	} else if rawurl != "" && StringInSlice(rawurl, client.GetRedirectURIs()) {
-		// If a redirect_uri was given and the clients knows it (simple string comparison!)
-		// return it.
+	} else if rawurl != "" && matcher(client.GetRedirectURIs(), rawurl) {
+		// If a redirect_uri was given and the clients knows it (simple string comparison, unless the matcher
+		// allows more) return it.
 		if parsed, err := url.Parse(rawurl); err == nil && IsValidRedirectURI(parsed) {
 			// If no redirect_uri was given and the client has exactly one valid redirect_uri registered, use that instead
 			return parsed, nil
@@ -82,9 +90,24 @@ func MatchRedirectURIWithClientRedirectURIs(rawurl string, client Client) (*url.
 // * https://tools.ietf.org/html/rfc3986#section-4.3
 //   absolute-URI  = scheme ":" hier-part [ "?" query ]
 // * https://tools.ietf.org/html/rfc6819#section-5.1.1
+// * https://tools.ietf.org/html/rfc8252#section-7.1
+//   Apps MUST use a URI scheme based on a domain name under their
+//   control, expressed in reverse order, as recommended by Section 3.8 of
+//   [RFC7595] for private-use URI schemes.
+//
+// Private-use schemes such as com.example.app:/oauth2redirect are valid redirect URIs, while the javascript, data,
+// vbscript and file schemes are not. URIs using http or https must contain a host.
 func IsValidRedirectURI(redirectURI *url.URL) bool {
 	// We need to explicitly check for a scheme
-	if !govalidator.IsRequestURL(redirectURI.String()) {
+	if !redirectURI.IsAbs() {
+		return false
+	}
+
+	if redirectURI.Scheme == "http" || redirectURI.Scheme == "https" {
+		if redirectURI.Hostname() == "" {
+			return false
+		}
+	} else if isForbiddenRedirectURIScheme(redirectURI.Scheme) {
 		return false
 	}
 
@@ -96,11 +119,30 @@ func IsValidRedirectURI(redirectURI *url.URL) bool {
 	return true
 }
 
+// IsRedirectURISecure returns false if the redirect URI uses http and does not point to the loopback interface, see
+// https://tools.ietf.org/html/rfc8252#section-8.3, or uses the javascript, data, vbscript or file scheme.
 func IsRedirectURISecure(redirectURI *url.URL) bool {
+	if isForbiddenRedirectURIScheme(redirectURI.Scheme) {
+		return false
+	}
 	return !(redirectURI.Scheme == "http" && !isLocalhost(redirectURI))
 }
 
+// forbiddenRedirectURISchemes are schemes which make the user agent execute or load content instead of delivering
+// the response to a client.
+var forbiddenRedirectURISchemes = []string{"javascript", "data", "vbscript", "file"}
+
+// isForbiddenRedirectURIScheme returns true if the scheme must never be used by redirect URIs.
+func isForbiddenRedirectURIScheme(scheme string) bool {
+	return StringInSlice(strings.ToLower(scheme), forbiddenRedirectURISchemes)
+}
+
+// isLocalhost returns true if the host of the URI is "localhost" or a loopback IP literal such as 127.0.0.1 or [::1].
 func isLocalhost(redirectURI *url.URL) bool {
-	host := strings.Split(redirectURI.Host, ":")[0]
-	return strings.HasSuffix(host, "localhost") || host == "127.0.0.1"
+	host := redirectURI.Hostname()
+	if strings.ToLower(host) == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
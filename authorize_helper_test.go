@@ -18,8 +18,13 @@ func TestIsLocalhost(t *testing.T) {
 		{expect: true, rawurl: "https://localhost:1234"},
 		{expect: true, rawurl: "https://127.0.0.1:1234"},
 		{expect: true, rawurl: "https://127.0.0.1"},
-		{expect: true, rawurl: "https://test.localhost:1234"},
-		{expect: true, rawurl: "https://test.localhost"},
+		{expect: false, rawurl: "https://test.localhost:1234"},
+		{expect: false, rawurl: "https://test.localhost"},
+		{expect: false, rawurl: "https://evillocalhost"},
+		{expect: false, rawurl: "https://localhost.example.com"},
+		{expect: true, rawurl: "http://[::1]:1234"},
+		{expect: true, rawurl: "http://127.0.0.2"},
+		{expect: false, rawurl: "http://128.0.0.1"},
 	} {
 		u, _ := url.Parse(c.rawurl)
 		assert.Equal(t, c.expect, isLocalhost(u), "case %d", k)
@@ -133,6 +138,51 @@ func TestDoesClientWhiteListRedirect(t *testing.T) {
 			url:     "https://bar.com/cb123",
 			isError: true,
 		},
+		{
+			client:   &DefaultClient{RedirectURIs: []string{"http://127.0.0.1/cb"}},
+			url:      "http://127.0.0.1:51004/cb",
+			expected: "http://127.0.0.1:51004/cb",
+		},
+		{
+			client:   &DefaultClient{RedirectURIs: []string{"http://127.0.0.1:8080/cb?foo=bar"}},
+			url:      "http://127.0.0.1:51004/cb?foo=bar",
+			expected: "http://127.0.0.1:51004/cb?foo=bar",
+		},
+		{
+			client:   &DefaultClient{RedirectURIs: []string{"http://[::1]/cb"}},
+			url:      "http://[::1]:51004/cb",
+			expected: "http://[::1]:51004/cb",
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"http://127.0.0.1/cb"}},
+			url:     "http://127.0.0.1:51004/other",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"http://127.0.0.1/cb"}},
+			url:     "http://[::1]:51004/cb",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"http://localhost/cb"}},
+			url:     "http://localhost:51004/cb",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"https://127.0.0.1/cb"}},
+			url:     "https://127.0.0.1:51004/cb",
+			isError: true,
+		},
+		{
+			client:   &DefaultClient{RedirectURIs: []string{"com.example.app:/oauth2redirect/example-path"}},
+			url:      "com.example.app:/oauth2redirect/example-path",
+			expected: "com.example.app:/oauth2redirect/example-path",
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"com.example.app:/oauth2redirect/example-path"}},
+			url:     "com.example.app:/oauth2redirect/other",
+			isError: true,
+		},
 	} {
 		redir, err := MatchRedirectURIWithClientRedirectURIs(c.url, c.client)
 		assert.Equal(t, c.isError, err != nil, "%d: %s", k, err)
@@ -152,11 +202,43 @@ func TestIsRedirectURISecure(t *testing.T) {
 		{u: "http://google.com", err: true},
 		{u: "https://google.com", err: false},
 		{u: "http://localhost", err: false},
-		{u: "http://test.localhost", err: false},
+		{u: "http://test.localhost", err: true},
+		{u: "http://evillocalhost", err: true},
+		{u: "http://127.0.0.1:1234/cb", err: false},
+		{u: "http://[::1]:1234/cb", err: false},
 		{u: "wta://auth", err: false},
+		{u: "com.example.app:/oauth2redirect", err: false},
+		{u: "javascript:alert(1)", err: true},
+		{u: "data:text/html,foo", err: true},
+		{u: "vbscript:msgbox(1)", err: true},
+		{u: "file:///etc/passwd", err: true},
 	} {
 		uu, err := url.Parse(c.u)
 		require.Nil(t, err)
 		assert.Equal(t, !c.err, IsRedirectURISecure(uu), "case %d", d)
 	}
 }
+
+func TestIsValidRedirectURI(t *testing.T) {
+	for k, c := range []struct {
+		rawurl string
+		expect bool
+	}{
+		{rawurl: "https://foo.com/cb", expect: true},
+		{rawurl: "http://127.0.0.1:51004/cb", expect: true},
+		{rawurl: "http://[::1]:51004/cb", expect: true},
+		{rawurl: "com.example.app:/oauth2redirect/example-path", expect: true},
+		{rawurl: "wta://auth", expect: true},
+		{rawurl: "javascript:alert(1)", expect: false},
+		{rawurl: "data:text/html,<script>alert(1)</script>", expect: false},
+		{rawurl: "vbscript:msgbox(1)", expect: false},
+		{rawurl: "file:///etc/passwd", expect: false},
+		{rawurl: "https://foo.com/cb#fragment", expect: false},
+		{rawurl: "/cb", expect: false},
+		{rawurl: "https:///cb", expect: false},
+	} {
+		u, err := url.Parse(c.rawurl)
+		require.Nil(t, err)
+		assert.Equal(t, c.expect, IsValidRedirectURI(u), "case %d", k)
+	}
+}
@@ -11,6 +11,9 @@ type AuthorizeRequest struct {
 	State                string    `json:"state" gorethink:"state"`
 	HandledResponseTypes Arguments `json:"handledResponseTypes" gorethink:"handledResponseTypes"`
 
+	// redirectURIMatcher is the matcher the redirect URI was validated with by Fosite.NewAuthorizeRequest.
+	redirectURIMatcher RedirectURIMatcher
+
 	Request
 }
 
@@ -33,7 +36,15 @@ func (d *AuthorizeRequest) IsRedirectURIValid() bool {
 		return false
 	}
 
-	redirectURI, err := MatchRedirectURIWithClientRedirectURIs(raw, d.GetClient())
+	matcher := d.redirectURIMatcher
+	if matcher == nil {
+		var err error
+		if matcher, err = GetRedirectURIMatcher(d.GetClient(), nil, nil); err != nil {
+			return false
+		}
+	}
+
+	redirectURI, err := MatchRedirectURI(raw, d.GetClient(), matcher)
 	if err != nil {
 		return false
 	}
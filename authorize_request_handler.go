@@ -34,7 +34,13 @@ func (c *Fosite) NewAuthorizeRequest(ctx context.Context, r *http.Request) (Auth
 	}
 
 	// Validate redirect uri
-	redirectURI, err := MatchRedirectURIWithClientRedirectURIs(rawRedirURI, client)
+	matcher, err := c.GetRedirectURIMatcher(client)
+	if err != nil {
+		return request, err
+	}
+	request.redirectURIMatcher = matcher
+
+	redirectURI, err := MatchRedirectURI(rawRedirURI, client, matcher)
 	if err != nil {
 		return request, errors.Wrap(ErrInvalidRequest, err.Error())
 	} else if !IsValidRedirectURI(redirectURI) {
@@ -64,5 +70,27 @@ func (c *Fosite) NewAuthorizeRequest(ctx context.Context, r *http.Request) (Auth
 
 	// Remove empty items from arrays
 	request.SetRequestedScopes(removeEmpty(strings.Split(r.Form.Get("scope"), " ")))
+	if err := c.validateScopes(request, true); err != nil {
+		return request, err
+	}
+
+	audience, err := GetAudiences(r.Form)
+	if err != nil {
+		return request, err
+	}
+	request.SetRequestedAudience(audience)
+	if err := c.validateAudience(request); err != nil {
+		return request, err
+	}
+
+	details, err := GetAuthorizationDetails(r.Form)
+	if err != nil {
+		return request, err
+	}
+	request.SetRequestedAuthorizationDetails(details)
+	if err := c.validateAuthorizationDetails(ctx, request); err != nil {
+		return request, err
+	}
+
 	return request, nil
 }
@@ -132,6 +132,22 @@ func TestNewAuthorizeRequest(t *testing.T) {
 				store.EXPECT().GetClient(gomock.Any(), "1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}}, nil)
 			},
 		},
+		/* unsupported authorization details */
+		{
+			desc: "unsupported authorization details type",
+			conf: &Fosite{Store: store, AuthorizationDetailTypes: AuthorizationDetailTypes{"payment_initiation": nil}},
+			query: url.Values{
+				"redirect_uri":          {"https://foo.bar/cb"},
+				"client_id":             {"1234"},
+				"response_type":         {"code"},
+				"state":                 {"strong-state"},
+				"authorization_details": {`[{"type":"account_information"}]`},
+			},
+			expectedError: ErrInvalidAuthorizationDetails,
+			mock: func() {
+				store.EXPECT().GetClient(gomock.Any(), "1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}}, nil)
+			},
+		},
 		/* success case */
 		{
 			desc: "should pass",
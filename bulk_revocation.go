@@ -0,0 +1,80 @@
+package fosite
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// BulkRevocationFilter selects the tokens revoked by a bulk revocation. Empty fields match any value, but at least
+// one field must be set.
+type BulkRevocationFilter struct {
+	// Subject matches the tokens issued for this subject (resource owner).
+	Subject string
+
+	// ClientID matches the tokens issued to this client.
+	ClientID string
+}
+
+// Matches returns true if a token issued for the subject to the client is selected by the filter.
+func (f BulkRevocationFilter) Matches(subject, clientID string) bool {
+	if f.Subject == "" && f.ClientID == "" {
+		return false
+	}
+	return (f.Subject == "" || f.Subject == subject) && (f.ClientID == "" || f.ClientID == clientID)
+}
+
+// BulkRevocationHandler is an optional extension of RevocationHandler for handlers that revoke all tokens matching
+// a filter, e.g. when a user resets their password or when a client is deleted.
+type BulkRevocationHandler interface {
+	// RevokeTokens revokes all tokens, authorization codes and sessions matching the filter. If the handler can not
+	// revoke tokens in bulk, this method should return ErrUnknownRequest.
+	RevokeTokens(ctx context.Context, filter BulkRevocationFilter) error
+}
+
+// RevokeTokensBySubject revokes all tokens issued for the subject, regardless of the client.
+func (f *Fosite) RevokeTokensBySubject(ctx context.Context, subject string) error {
+	if subject == "" {
+		return errors.Wrap(ErrInvalidRequest, "Subject must not be empty")
+	}
+	return f.revokeTokens(ctx, BulkRevocationFilter{Subject: subject})
+}
+
+// RevokeTokensByClient revokes all tokens issued to the client, regardless of the subject.
+func (f *Fosite) RevokeTokensByClient(ctx context.Context, clientID string) error {
+	if clientID == "" {
+		return errors.Wrap(ErrInvalidRequest, "Client ID must not be empty")
+	}
+	return f.revokeTokens(ctx, BulkRevocationFilter{ClientID: clientID})
+}
+
+// RevokeTokensBySubjectAndClient revokes all tokens issued for the subject to the client.
+func (f *Fosite) RevokeTokensBySubjectAndClient(ctx context.Context, subject, clientID string) error {
+	if subject == "" || clientID == "" {
+		return errors.Wrap(ErrInvalidRequest, "Subject and client ID must not be empty")
+	}
+	return f.revokeTokens(ctx, BulkRevocationFilter{Subject: subject, ClientID: clientID})
+}
+
+func (f *Fosite) revokeTokens(ctx context.Context, filter BulkRevocationFilter) error {
+	var found bool
+	for _, handler := range f.RevocationHandlers {
+		bh, ok := handler.(BulkRevocationHandler)
+		if !ok {
+			continue
+		}
+
+		if err := bh.RevokeTokens(ctx, filter); errors.Cause(err) == ErrUnknownRequest {
+			// do nothing
+		} else if err != nil {
+			return err
+		} else {
+			found = true
+		}
+	}
+
+	if !found {
+		return errors.Wrap(ErrMisconfiguration, "No revocation handler supports bulk revocation")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package fosite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type bulkRevocationTestHandler struct {
+	filters []BulkRevocationFilter
+	err     error
+}
+
+func (h *bulkRevocationTestHandler) RevokeToken(_ context.Context, _ string, _ TokenType, _ Client) error {
+	return nil
+}
+
+func (h *bulkRevocationTestHandler) RevokeTokens(_ context.Context, filter BulkRevocationFilter) error {
+	h.filters = append(h.filters, filter)
+	return h.err
+}
+
+func TestBulkRevocationFilter_Matches(t *testing.T) {
+	for k, c := range []struct {
+		filter   BulkRevocationFilter
+		subject  string
+		clientID string
+		expect   bool
+	}{
+		{filter: BulkRevocationFilter{Subject: "peter"}, subject: "peter", clientID: "foo", expect: true},
+		{filter: BulkRevocationFilter{Subject: "peter"}, subject: "alice", clientID: "foo", expect: false},
+		{filter: BulkRevocationFilter{ClientID: "foo"}, subject: "alice", clientID: "foo", expect: true},
+		{filter: BulkRevocationFilter{ClientID: "foo"}, subject: "alice", clientID: "bar", expect: false},
+		{filter: BulkRevocationFilter{Subject: "peter", ClientID: "foo"}, subject: "peter", clientID: "foo", expect: true},
+		{filter: BulkRevocationFilter{Subject: "peter", ClientID: "foo"}, subject: "peter", clientID: "bar", expect: false},
+		{filter: BulkRevocationFilter{}, subject: "peter", clientID: "foo", expect: false},
+	} {
+		assert.Equal(t, c.expect, c.filter.Matches(c.subject, c.clientID), "(%d)", k)
+	}
+}
+
+func TestRevokeTokensInBulk(t *testing.T) {
+	h := &bulkRevocationTestHandler{}
+	f := &Fosite{RevocationHandlers: RevocationHandlers{h}}
+
+	assert.Nil(t, f.RevokeTokensBySubject(nil, "peter"))
+	assert.Nil(t, f.RevokeTokensByClient(nil, "foo"))
+	assert.Nil(t, f.RevokeTokensBySubjectAndClient(nil, "peter", "foo"))
+	assert.Equal(t, []BulkRevocationFilter{
+		{Subject: "peter"},
+		{ClientID: "foo"},
+		{Subject: "peter", ClientID: "foo"},
+	}, h.filters)
+
+	assert.Equal(t, ErrInvalidRequest, errors.Cause(f.RevokeTokensBySubject(nil, "")))
+	assert.Equal(t, ErrInvalidRequest, errors.Cause(f.RevokeTokensByClient(nil, "")))
+	assert.Equal(t, ErrInvalidRequest, errors.Cause(f.RevokeTokensBySubjectAndClient(nil, "peter", "")))
+
+	h.err = errors.WithStack(ErrUnknownRequest)
+	assert.Equal(t, ErrMisconfiguration, errors.Cause(f.RevokeTokensBySubject(nil, "peter")))
+
+	h.err = errors.WithStack(ErrServerError)
+	assert.Equal(t, ErrServerError, errors.Cause(f.RevokeTokensBySubject(nil, "peter")))
+
+	f.RevocationHandlers = RevocationHandlers{}
+	assert.Equal(t, ErrMisconfiguration, errors.Cause(f.RevokeTokensBySubject(nil, "peter")))
+}
@@ -1,5 +1,10 @@
 package fosite
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Client represents a client or an app.
 type Client interface {
 	// GetID returns the client ID.
@@ -24,6 +29,75 @@ type Client interface {
 	IsPublic() bool
 }
 
+// ClientWithAudience is an optional extension of Client for clients that may request audience-restricted tokens.
+type ClientWithAudience interface {
+	// GetAudience returns the audiences (e.g. resource server URLs) this client is allowed to request tokens for.
+	GetAudience() Arguments
+
+	Client
+}
+
+// ClientWithPostLogoutRedirectURIs is an optional extension of Client for clients that registered the URIs they may
+// be redirected to after logout, see https://openid.net/specs/openid-connect-rpinitiated-1_0.html#ClientMetadata
+type ClientWithPostLogoutRedirectURIs interface {
+	// GetPostLogoutRedirectURIs returns the client's allowed post logout redirect URIs.
+	GetPostLogoutRedirectURIs() []string
+
+	Client
+}
+
+// ClientWithBackChannelLogout is an optional extension of Client for clients that registered for back-channel logout,
+// see https://openid.net/specs/openid-connect-backchannel-1_0.html#BCRegistration
+type ClientWithBackChannelLogout interface {
+	// GetBackChannelLogoutURI returns the URI logout tokens are sent to, or an empty string if the client does not
+	// support back-channel logout.
+	GetBackChannelLogoutURI() string
+
+	// IsBackChannelLogoutSessionRequired returns true if logout tokens sent to the client must contain a sid claim.
+	IsBackChannelLogoutSessionRequired() bool
+
+	Client
+}
+
+// ClientWithFrontChannelLogout is an optional extension of Client for clients that registered for front-channel
+// logout, see https://openid.net/specs/openid-connect-frontchannel-1_0.html#RPLogout
+type ClientWithFrontChannelLogout interface {
+	// GetFrontChannelLogoutURI returns the URI rendered in an iframe on logout, or an empty string if the client does
+	// not support front-channel logout.
+	GetFrontChannelLogoutURI() string
+
+	// IsFrontChannelLogoutSessionRequired returns true if the iss and sid query parameters must be added to the
+	// front-channel logout URI.
+	IsFrontChannelLogoutSessionRequired() bool
+
+	Client
+}
+
+// ClientWithSubjectType is an optional extension of Client for clients that registered a subject type, see
+// https://openid.net/specs/openid-connect-core-1_0.html#SubjectIDTypes
+type ClientWithSubjectType interface {
+	// GetSubjectType returns either SubjectTypePublic or SubjectTypePairwise. An empty string means public.
+	GetSubjectType() string
+
+	// GetSectorIdentifierURI returns the URI of the JSON array of redirect URIs that pairwise subject identifiers
+	// are calculated for, or an empty string if the sector is the host of the redirect URIs.
+	GetSectorIdentifierURI() string
+
+	Client
+}
+
+// ClientWithSoftwareStatement is an optional extension of Client for clients registered with software metadata, see
+// https://tools.ietf.org/html/rfc7591#section-2
+type ClientWithSoftwareStatement interface {
+	GetSoftwareID() string
+	GetSoftwareVersion() string
+
+	// GetSoftwareStatement returns the software statement the client was registered with, if any.
+	GetSoftwareStatement() string
+
+	Client
+}
+
 // DefaultClient is a simple default implementation of the Client interface.
 type DefaultClient struct {
 	ID            string   `json:"id"`
@@ -32,7 +106,39 @@ type DefaultClient struct {
 	GrantTypes    []string `json:"grant_types"`
 	ResponseTypes []string `json:"response_types"`
 	Scopes        []string `json:"scopes"`
+	Audience      []string `json:"audience"`
 	Public        bool     `json:"public"`
+
+	// RedirectURIMatcher is the name of the matcher used for RedirectURIs, e.g. RedirectURIMatcherWildcard. Empty
+	// means the default matcher.
+	RedirectURIMatcher string `json:"redirect_uri_matcher,omitempty"`
+
+	// FirstParty marks trusted first-party clients, which do not need the subject's consent.
+	FirstParty bool `json:"first_party,omitempty"`
+
+	// SecretExpiresAt is the time Secret expires at. The zero value means it never expires.
+	SecretExpiresAt time.Time `json:"client_secret_expires_at,omitempty"`
+
+	// RotatedSecrets are further secrets accepted besides Secret, e.g. the previous secret during a rotation.
+	RotatedSecrets []ClientSecret `json:"rotated_client_secrets,omitempty"`
+
+	PostLogoutRedirectURIs            []string `json:"post_logout_redirect_uris,omitempty"`
+	BackChannelLogoutURI              string   `json:"backchannel_logout_uri,omitempty"`
+	BackChannelLogoutSessionRequired  bool     `json:"backchannel_logout_session_required,omitempty"`
+	FrontChannelLogoutURI             string   `json:"frontchannel_logout_uri,omitempty"`
+	FrontChannelLogoutSessionRequired bool     `json:"frontchannel_logout_session_required,omitempty"`
+	SubjectType                       string   `json:"subject_type,omitempty"`
+	SectorIdentifierURI               string   `json:"sector_identifier_uri,omitempty"`
+
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method,omitempty"`
+	JSONWebKeysURI          string          `json:"jwks_uri,omitempty"`
+	JSONWebKeys             json.RawMessage `json:"jwks,omitempty"`
+	SoftwareID              string          `json:"software_id,omitempty"`
+	SoftwareVersion         string          `json:"software_version,omitempty"`
+	SoftwareStatement       string          `json:"software_statement,omitempty"`
+
+	// RegistrationAccessToken is the hashed token authorizing client configuration requests (RFC 7592).
+	RegistrationAccessToken []byte `json:"registration_access_token,omitempty"`
 }
 
 func (c *DefaultClient) GetID() string {
@@ -47,6 +153,103 @@ func (c *DefaultClient) GetRedirectURIs() []string {
 	return c.RedirectURIs
 }
 
+func (c *DefaultClient) IsFirstParty() bool {
+	return c.FirstParty
+}
+
+func (c *DefaultClient) GetRedirectURIMatcher() string {
+	return c.RedirectURIMatcher
+}
+
+func (c *DefaultClient) GetPostLogoutRedirectURIs() []string {
+	return c.PostLogoutRedirectURIs
+}
+
+func (c *DefaultClient) GetBackChannelLogoutURI() string {
+	return c.BackChannelLogoutURI
+}
+
+func (c *DefaultClient) IsBackChannelLogoutSessionRequired() bool {
+	return c.BackChannelLogoutSessionRequired
+}
+
+func (c *DefaultClient) GetFrontChannelLogoutURI() string {
+	return c.FrontChannelLogoutURI
+}
+
+func (c *DefaultClient) IsFrontChannelLogoutSessionRequired() bool {
+	return c.FrontChannelLogoutSessionRequired
+}
+
+func (c *DefaultClient) GetSubjectType() string {
+	return c.SubjectType
+}
+
+func (c *DefaultClient) GetSectorIdentifierURI() string {
+	return c.SectorIdentifierURI
+}
+
+// GetTokenEndpointAuthMethod returns the registered client authentication method. Defaults to none for public clients
+// and client_secret_basic otherwise.
+func (c *DefaultClient) GetTokenEndpointAuthMethod() string {
+	if c.TokenEndpointAuthMethod != "" {
+		return c.TokenEndpointAuthMethod
+	} else if c.Public {
+		return "none"
+	}
+	return "client_secret_basic"
+}
+
+func (c *DefaultClient) GetJSONWebKeysURI() string {
+	return c.JSONWebKeysURI
+}
+
+func (c *DefaultClient) GetJSONWebKeys() json.RawMessage {
+	return c.JSONWebKeys
+}
+
+func (c *DefaultClient) GetSoftwareID() string {
+	return c.SoftwareID
+}
+
+func (c *DefaultClient) GetSoftwareVersion() string {
+	return c.SoftwareVersion
+}
+
+func (c *DefaultClient) GetSoftwareStatement() string {
+	return c.SoftwareStatement
+}
+
+// GetHashedSecrets returns Secret followed by RotatedSecrets.
+func (c *DefaultClient) GetHashedSecrets() []ClientSecret {
+	secrets := []ClientSecret{{Hash: c.Secret, ExpiresAt: c.SecretExpiresAt}}
+	return append(secrets, c.RotatedSecrets...)
+}
+
+// RotateSecret replaces Secret with the given hash and keeps accepting the previous secret until previousExpiresAt.
+// A zero previousExpiresAt expires the previous secret immediately, it never keeps it forever. Rotated secrets which
+// already expired are removed.
+func (c *DefaultClient) RotateSecret(hash []byte, expiresAt, previousExpiresAt time.Time) {
+	now := time.Now().UTC()
+	var rotated []ClientSecret
+	if len(c.Secret) > 0 && !previousExpiresAt.IsZero() && previousExpiresAt.After(now) {
+		previous := ClientSecret{Hash: c.Secret, ExpiresAt: c.SecretExpiresAt}
+		if previous.ExpiresAt.IsZero() || previousExpiresAt.Before(previous.ExpiresAt) {
+			previous.ExpiresAt = previousExpiresAt
+		}
+		rotated = append(rotated, previous)
+	}
+	for _, secret := range c.RotatedSecrets {
+		if !secret.IsExpired(now) {
+			rotated = append(rotated, secret)
+		}
+	}
+
+	c.Secret = hash
+	c.SecretExpiresAt = expiresAt
+	c.RotatedSecrets = rotated
+}
+
 func (c *DefaultClient) GetHashedSecret() []byte {
 	return c.Secret
 }
@@ -55,6 +258,10 @@ func (c *DefaultClient) GetScopes() Arguments {
 	return c.Scopes
 }
 
+func (c *DefaultClient) GetAudience() Arguments {
+	return c.Audience
+}
+
 func (c *DefaultClient) GetGrantTypes() Arguments {
 	// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
 	//
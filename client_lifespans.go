@@ -0,0 +1,39 @@
+package fosite
+
+import "time"
+
+// ClientWithCustomTokenLifespans is an optional extension of Client. Clients implementing it can override the
+// lifespans configured for the handlers, for example to issue short-lived access tokens to machine-to-machine
+// clients only.
+type ClientWithCustomTokenLifespans interface {
+	// GetEffectiveLifespan returns the lifespan of a token of the given type that is issued using the given grant
+	// type (e.g. authorization_code, implicit, refresh_token), or fallback if the client does not override it.
+	GetEffectiveLifespan(grantType string, tokenType TokenType, fallback time.Duration) time.Duration
+
+	Client
+}
+
+// GetEffectiveLifespan returns the client's lifespan for the token and grant type if the client implements
+// ClientWithCustomTokenLifespans, and fallback otherwise.
+func GetEffectiveLifespan(c Client, grantType string, tokenType TokenType, fallback time.Duration) time.Duration {
+	if clc, ok := c.(ClientWithCustomTokenLifespans); ok {
+		return clc.GetEffectiveLifespan(grantType, tokenType, fallback)
+	}
+	return fallback
+}
+
+// DefaultClientWithCustomTokenLifespans is a DefaultClient that overrides token lifespans per grant type.
+type DefaultClientWithCustomTokenLifespans struct {
+	*DefaultClient
+
+	// TokenLifespans maps a grant type to the lifespans of the tokens issued using it. Missing or zero entries fall
+	// back to the handler's configuration.
+	TokenLifespans map[string]map[TokenType]time.Duration `json:"token_lifespans,omitempty"`
+}
+
+func (c *DefaultClientWithCustomTokenLifespans) GetEffectiveLifespan(grantType string, tokenType TokenType, fallback time.Duration) time.Duration {
+	if lifespan := c.TokenLifespans[grantType][tokenType]; lifespan != 0 {
+		return lifespan
+	}
+	return fallback
+}
@@ -0,0 +1,23 @@
+package fosite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEffectiveLifespan(t *testing.T) {
+	c := &DefaultClientWithCustomTokenLifespans{
+		DefaultClient: &DefaultClient{ID: "foo"},
+		TokenLifespans: map[string]map[TokenType]time.Duration{
+			"client_credentials": {AccessToken: 5 * time.Minute},
+		},
+	}
+
+	assert.Equal(t, 5*time.Minute, GetEffectiveLifespan(c, "client_credentials", AccessToken, time.Hour))
+	assert.Equal(t, time.Hour, GetEffectiveLifespan(c, "client_credentials", RefreshToken, time.Hour))
+	assert.Equal(t, time.Hour, GetEffectiveLifespan(c, "authorization_code", AccessToken, time.Hour))
+	assert.Equal(t, time.Hour, GetEffectiveLifespan(c.DefaultClient, "client_credentials", AccessToken, time.Hour))
+	assert.Equal(t, time.Hour, GetEffectiveLifespan(nil, "client_credentials", AccessToken, time.Hour))
+}
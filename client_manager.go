@@ -8,3 +8,17 @@ type ClientManager interface {
 	// if the client does not exist or another error occurred.
 	GetClient(ctx context.Context, id string) (Client, error)
 }
+
+// WritableClientManager is a ClientManager that can also persist clients, e.g. for dynamic client registration.
+type WritableClientManager interface {
+	// CreateClient stores a new client.
+	CreateClient(ctx context.Context, client Client) error
+
+	// UpdateClient replaces an existing client or returns ErrNotFound.
+	UpdateClient(ctx context.Context, client Client) error
+
+	// DeleteClient removes a client or returns ErrNotFound.
+	DeleteClient(ctx context.Context, id string) error
+
+	ClientManager
+}
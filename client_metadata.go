@@ -0,0 +1,343 @@
+package fosite
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ClientMetadata is the client metadata sent in dynamic client registration requests as defined in
+// https://tools.ietf.org/html/rfc7591#section-2 and
+// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+type ClientMetadata struct {
+	RedirectURIs            []string        `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes              []string        `json:"grant_types,omitempty"`
+	ResponseTypes           []string        `json:"response_types,omitempty"`
+	Scope                   string          `json:"scope,omitempty"`
+	Audience                []string        `json:"audience,omitempty"`
+	JSONWebKeysURI          string          `json:"jwks_uri,omitempty"`
+	JSONWebKeys             json.RawMessage `json:"jwks,omitempty"`
+	SoftwareID              string          `json:"software_id,omitempty"`
+	SoftwareVersion         string          `json:"software_version,omitempty"`
+	SoftwareStatement       string          `json:"software_statement,omitempty"`
+
+	SubjectType                       string   `json:"subject_type,omitempty"`
+	SectorIdentifierURI               string   `json:"sector_identifier_uri,omitempty"`
+	PostLogoutRedirectURIs            []string `json:"post_logout_redirect_uris,omitempty"`
+	BackChannelLogoutURI              string   `json:"backchannel_logout_uri,omitempty"`
+	BackChannelLogoutSessionRequired  bool     `json:"backchannel_logout_session_required,omitempty"`
+	FrontChannelLogoutURI             string   `json:"frontchannel_logout_uri,omitempty"`
+	FrontChannelLogoutSessionRequired bool     `json:"frontchannel_logout_session_required,omitempty"`
+
+	ApplicationType                     string   `json:"application_type,omitempty"`
+	ClientName                          string   `json:"client_name,omitempty"`
+	ClientURI                           string   `json:"client_uri,omitempty"`
+	LogoURI                             string   `json:"logo_uri,omitempty"`
+	PolicyURI                           string   `json:"policy_uri,omitempty"`
+	TermsOfServiceURI                   string   `json:"tos_uri,omitempty"`
+	Contacts                            []string `json:"contacts,omitempty"`
+	TokenEndpointAuthSigningAlgorithm   string   `json:"token_endpoint_auth_signing_alg,omitempty"`
+	IDTokenSignedResponseAlgorithm      string   `json:"id_token_signed_response_alg,omitempty"`
+	IDTokenEncryptedResponseAlgorithm   string   `json:"id_token_encrypted_response_alg,omitempty"`
+	IDTokenEncryptedResponseEncryption  string   `json:"id_token_encrypted_response_enc,omitempty"`
+	UserInfoSignedResponseAlgorithm     string   `json:"userinfo_signed_response_alg,omitempty"`
+	UserInfoEncryptedResponseAlgorithm  string   `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserInfoEncryptedResponseEncryption string   `json:"userinfo_encrypted_response_enc,omitempty"`
+	RequestObjectSigningAlgorithm       string   `json:"request_object_signing_alg,omitempty"`
+	RequestURIs                         []string `json:"request_uris,omitempty"`
+	DefaultMaxAge                       int64    `json:"default_max_age,omitempty"`
+	RequireAuthTime                     bool     `json:"require_auth_time,omitempty"`
+	DefaultACRValues                    []string `json:"default_acr_values,omitempty"`
+	InitiateLoginURI                    string   `json:"initiate_login_uri,omitempty"`
+}
+
+// DefaultTokenEndpointAuthMethods are the client authentication methods supported by the token endpoint.
+var DefaultTokenEndpointAuthMethods = []string{"client_secret_basic", "none"}
+
+// registrableGrantTypes are the grant types clients may register besides extension grants identified by an absolute
+// URI.
+var registrableGrantTypes = []string{"authorization_code", "implicit", "password", "client_credentials", "refresh_token"}
+
+// ValidateClientMetadata validates client metadata as defined in https://tools.ietf.org/html/rfc7591#section-2 and
+// fills in the default grant types, response types, authentication method, application type and ID token signing
+// algorithm.
+//
+//   The authorization server MUST ensure that the "grant_types" and
+//   "response_types" values are consistent with each other. [...]
+//
+//   "jwks_uri" and "jwks" parameters MUST NOT both be present in the same
+//   request or response.
+//
+// Invalid redirect URIs are reported as ErrInvalidRedirectURI, all other problems as ErrInvalidClientMetadata.
+func ValidateClientMetadata(m *ClientMetadata, authMethods []string) error {
+	if len(m.GrantTypes) == 0 {
+		m.GrantTypes = []string{"authorization_code"}
+	}
+	if len(m.ResponseTypes) == 0 {
+		m.ResponseTypes = []string{"code"}
+	}
+	if m.TokenEndpointAuthMethod == "" {
+		m.TokenEndpointAuthMethod = "client_secret_basic"
+	}
+	if m.ApplicationType == "" {
+		m.ApplicationType = "web"
+	}
+	if m.IDTokenSignedResponseAlgorithm == "" {
+		m.IDTokenSignedResponseAlgorithm = "RS256"
+	}
+
+	for _, grantType := range m.GrantTypes {
+		if !StringInSlice(grantType, registrableGrantTypes) && !isAbsoluteURI(grantType) {
+			return errors.Wrapf(ErrInvalidClientMetadata, "Grant type %s is not supported", grantType)
+		}
+	}
+
+	grantTypes := Arguments(m.GrantTypes)
+	var usesCode, usesImplicit bool
+	for _, responseType := range m.ResponseTypes {
+		for _, part := range strings.Split(responseType, " ") {
+			switch part {
+			case "code":
+				usesCode = true
+				if !grantTypes.Has("authorization_code") {
+					return errors.Wrapf(ErrInvalidClientMetadata, "Response type %s requires grant type authorization_code", responseType)
+				}
+			case "token", "id_token":
+				usesImplicit = true
+				if !grantTypes.Has("implicit") {
+					return errors.Wrapf(ErrInvalidClientMetadata, "Response type %s requires grant type implicit", responseType)
+				}
+			case "none":
+			default:
+				return errors.Wrapf(ErrInvalidClientMetadata, "Response type %s is not supported", responseType)
+			}
+		}
+	}
+	if grantTypes.Has("authorization_code") && !usesCode {
+		return errors.Wrap(ErrInvalidClientMetadata, "Grant type authorization_code requires a response type containing code")
+	} else if grantTypes.Has("implicit") && !usesImplicit {
+		return errors.Wrap(ErrInvalidClientMetadata, "Grant type implicit requires a response type containing token or id_token")
+	}
+
+	if !StringInSlice(m.TokenEndpointAuthMethod, authMethods) {
+		return errors.Wrapf(ErrInvalidClientMetadata, "Token endpoint authentication method %s is not supported", m.TokenEndpointAuthMethod)
+	} else if m.TokenEndpointAuthMethod == "none" && grantTypes.Has("client_credentials") {
+		return errors.Wrap(ErrInvalidClientMetadata, "Grant type client_credentials requires client authentication")
+	} else if (m.TokenEndpointAuthMethod == "private_key_jwt" || m.TokenEndpointAuthMethod == "tls_client_auth") && m.JSONWebKeysURI == "" && len(m.JSONWebKeys) == 0 {
+		return errors.Wrapf(ErrInvalidClientMetadata, "Token endpoint authentication method %s requires jwks or jwks_uri", m.TokenEndpointAuthMethod)
+	}
+
+	if grantTypes.Has("authorization_code") || grantTypes.Has("implicit") {
+		if len(m.RedirectURIs) == 0 {
+			return errors.Wrap(ErrInvalidRedirectURI, "Redirect URIs are required by the authorization_code and implicit grant types")
+		}
+	}
+	for _, raw := range m.RedirectURIs {
+		if err := validateRegisteredURI(raw); err != nil {
+			return errors.Wrap(ErrInvalidRedirectURI, err.Error())
+		}
+	}
+	for _, raw := range m.PostLogoutRedirectURIs {
+		if err := validateRegisteredURI(raw); err != nil {
+			return errors.Wrap(ErrInvalidClientMetadata, err.Error())
+		}
+	}
+	for _, raw := range []string{m.BackChannelLogoutURI, m.FrontChannelLogoutURI} {
+		if raw == "" {
+			continue
+		} else if err := validateRegisteredURI(raw); err != nil {
+			return errors.Wrap(ErrInvalidClientMetadata, err.Error())
+		}
+	}
+
+	if m.JSONWebKeysURI != "" && len(m.JSONWebKeys) > 0 {
+		return errors.Wrap(ErrInvalidClientMetadata, "Parameters jwks and jwks_uri must not both be set")
+	} else if m.JSONWebKeysURI != "" && !isHTTPSURI(m.JSONWebKeysURI) {
+		return errors.Wrap(ErrInvalidClientMetadata, "Parameter jwks_uri must be an absolute https URI")
+	} else if len(m.JSONWebKeys) > 0 {
+		if err := validateJSONWebKeySet(m.JSONWebKeys); err != nil {
+			return errors.Wrapf(ErrInvalidClientMetadata, "Parameter jwks is invalid: %s", err)
+		}
+	}
+
+	switch m.SubjectType {
+	case "", SubjectTypePublic, SubjectTypePairwise:
+	default:
+		return errors.Wrapf(ErrInvalidClientMetadata, "Subject type %s is not supported", m.SubjectType)
+	}
+	if m.SectorIdentifierURI != "" && !isHTTPSURI(m.SectorIdentifierURI) {
+		return errors.Wrap(ErrInvalidClientMetadata, "Parameter sector_identifier_uri must be an absolute https URI")
+	}
+
+	switch m.ApplicationType {
+	case "web", "native":
+	default:
+		return errors.Wrapf(ErrInvalidClientMetadata, "Application type %s is not supported", m.ApplicationType)
+	}
+	if m.IDTokenSignedResponseAlgorithm != "RS256" {
+		return errors.Wrapf(ErrInvalidClientMetadata, "ID token signing algorithm %s is not supported", m.IDTokenSignedResponseAlgorithm)
+	} else if m.IDTokenEncryptedResponseAlgorithm != "" {
+		return errors.Wrap(ErrInvalidClientMetadata, "ID token encryption is not supported")
+	} else if m.IDTokenEncryptedResponseEncryption != "" {
+		return errors.Wrap(ErrInvalidClientMetadata, "Parameter id_token_encrypted_response_enc requires id_token_encrypted_response_alg")
+	} else if m.UserInfoEncryptedResponseEncryption != "" && m.UserInfoEncryptedResponseAlgorithm == "" {
+		return errors.Wrap(ErrInvalidClientMetadata, "Parameter userinfo_encrypted_response_enc requires userinfo_encrypted_response_alg")
+	} else if m.DefaultMaxAge < 0 {
+		return errors.Wrap(ErrInvalidClientMetadata, "Parameter default_max_age must not be negative")
+	}
+	for _, raw := range []string{m.ClientURI, m.LogoURI, m.PolicyURI, m.TermsOfServiceURI, m.InitiateLoginURI} {
+		if raw != "" && !isHTTPSURI(raw) {
+			return errors.Wrapf(ErrInvalidClientMetadata, "URI %s must be an absolute https URI", raw)
+		}
+	}
+	for _, raw := range m.RequestURIs {
+		if !isHTTPSURI(raw) {
+			return errors.Wrapf(ErrInvalidClientMetadata, "Request URI %s must be an absolute https URI", raw)
+		}
+	}
+
+	return nil
+}
+
+// NewClientFromMetadata returns a client with the given id and metadata. The metadata should be validated using
+// ValidateClientMetadata first.
+func NewClientFromMetadata(id string, m *ClientMetadata) *DefaultOpenIDConnectClient {
+	return &DefaultOpenIDConnectClient{
+		DefaultClient: &DefaultClient{
+			ID:                                id,
+			RedirectURIs:                      m.RedirectURIs,
+			GrantTypes:                        m.GrantTypes,
+			ResponseTypes:                     m.ResponseTypes,
+			Scopes:                            removeEmpty(strings.Split(m.Scope, " ")),
+			Audience:                          m.Audience,
+			Public:                            m.TokenEndpointAuthMethod == "none",
+			TokenEndpointAuthMethod:           m.TokenEndpointAuthMethod,
+			JSONWebKeysURI:                    m.JSONWebKeysURI,
+			JSONWebKeys:                       m.JSONWebKeys,
+			SoftwareID:                        m.SoftwareID,
+			SoftwareVersion:                   m.SoftwareVersion,
+			SoftwareStatement:                 m.SoftwareStatement,
+			SubjectType:                       m.SubjectType,
+			SectorIdentifierURI:               m.SectorIdentifierURI,
+			PostLogoutRedirectURIs:            m.PostLogoutRedirectURIs,
+			BackChannelLogoutURI:              m.BackChannelLogoutURI,
+			BackChannelLogoutSessionRequired:  m.BackChannelLogoutSessionRequired,
+			FrontChannelLogoutURI:             m.FrontChannelLogoutURI,
+			FrontChannelLogoutSessionRequired: m.FrontChannelLogoutSessionRequired,
+		},
+		ApplicationType:                     m.ApplicationType,
+		ClientName:                          m.ClientName,
+		ClientURI:                           m.ClientURI,
+		LogoURI:                             m.LogoURI,
+		PolicyURI:                           m.PolicyURI,
+		TermsOfServiceURI:                   m.TermsOfServiceURI,
+		Contacts:                            m.Contacts,
+		TokenEndpointAuthSigningAlgorithm:   m.TokenEndpointAuthSigningAlgorithm,
+		IDTokenSignedResponseAlgorithm:      m.IDTokenSignedResponseAlgorithm,
+		IDTokenEncryptedResponseAlgorithm:   m.IDTokenEncryptedResponseAlgorithm,
+		IDTokenEncryptedResponseEncryption:  m.IDTokenEncryptedResponseEncryption,
+		UserInfoSignedResponseAlgorithm:     m.UserInfoSignedResponseAlgorithm,
+		UserInfoEncryptedResponseAlgorithm:  m.UserInfoEncryptedResponseAlgorithm,
+		UserInfoEncryptedResponseEncryption: m.UserInfoEncryptedResponseEncryption,
+		RequestObjectSigningAlgorithm:       m.RequestObjectSigningAlgorithm,
+		RequestURIs:                         m.RequestURIs,
+		DefaultMaxAge:                       m.DefaultMaxAge,
+		RequireAuthTime:                     m.RequireAuthTime,
+		DefaultACRValues:                    m.DefaultACRValues,
+		InitiateLoginURI:                    m.InitiateLoginURI,
+	}
+}
+
+// GetClientMetadata returns the registered metadata of a client. Metadata of the optional client extensions the
+// client does not implement is left empty.
+func GetClientMetadata(c Client) *ClientMetadata {
+	m := &ClientMetadata{
+		RedirectURIs:            c.GetRedirectURIs(),
+		TokenEndpointAuthMethod: GetTokenEndpointAuthMethod(c),
+		GrantTypes:              c.GetGrantTypes(),
+		ResponseTypes:           c.GetResponseTypes(),
+		Scope:                   strings.Join(c.GetScopes(), " "),
+	}
+
+	if ac, ok := c.(ClientWithAudience); ok {
+		m.Audience = ac.GetAudience()
+	}
+	if sc, ok := c.(ClientWithSoftwareStatement); ok {
+		m.SoftwareID = sc.GetSoftwareID()
+		m.SoftwareVersion = sc.GetSoftwareVersion()
+		m.SoftwareStatement = sc.GetSoftwareStatement()
+	}
+	if sc, ok := c.(ClientWithSubjectType); ok {
+		m.SubjectType = sc.GetSubjectType()
+		m.SectorIdentifierURI = sc.GetSectorIdentifierURI()
+	}
+	if pc, ok := c.(ClientWithPostLogoutRedirectURIs); ok {
+		m.PostLogoutRedirectURIs = pc.GetPostLogoutRedirectURIs()
+	}
+	if bc, ok := c.(ClientWithBackChannelLogout); ok {
+		m.BackChannelLogoutURI = bc.GetBackChannelLogoutURI()
+		m.BackChannelLogoutSessionRequired = bc.IsBackChannelLogoutSessionRequired()
+	}
+	if fc, ok := c.(ClientWithFrontChannelLogout); ok {
+		m.FrontChannelLogoutURI = fc.GetFrontChannelLogoutURI()
+		m.FrontChannelLogoutSessionRequired = fc.IsFrontChannelLogoutSessionRequired()
+	}
+	if oc, ok := c.(OpenIDConnectClient); ok {
+		m.JSONWebKeysURI = oc.GetJSONWebKeysURI()
+		m.JSONWebKeys = oc.GetJSONWebKeys()
+		m.ApplicationType = oc.GetApplicationType()
+		m.ClientName = oc.GetClientName()
+		m.ClientURI = oc.GetClientURI()
+		m.LogoURI = oc.GetLogoURI()
+		m.PolicyURI = oc.GetPolicyURI()
+		m.TermsOfServiceURI = oc.GetTermsOfServiceURI()
+		m.Contacts = oc.GetContacts()
+		m.TokenEndpointAuthSigningAlgorithm = oc.GetTokenEndpointAuthSigningAlgorithm()
+		m.IDTokenSignedResponseAlgorithm = oc.GetIDTokenSignedResponseAlgorithm()
+		m.IDTokenEncryptedResponseAlgorithm = oc.GetIDTokenEncryptedResponseAlgorithm()
+		m.IDTokenEncryptedResponseEncryption = oc.GetIDTokenEncryptedResponseEncryption()
+		m.UserInfoSignedResponseAlgorithm = oc.GetUserInfoSignedResponseAlgorithm()
+		m.UserInfoEncryptedResponseAlgorithm = oc.GetUserInfoEncryptedResponseAlgorithm()
+		m.UserInfoEncryptedResponseEncryption = oc.GetUserInfoEncryptedResponseEncryption()
+		m.RequestObjectSigningAlgorithm = oc.GetRequestObjectSigningAlgorithm()
+		m.RequestURIs = oc.GetRequestURIs()
+		m.DefaultMaxAge = oc.GetDefaultMaxAge()
+		m.RequireAuthTime = oc.IsAuthTimeRequired()
+		m.DefaultACRValues = oc.GetDefaultACRValues()
+		m.InitiateLoginURI = oc.GetInitiateLoginURI()
+	}
+	return m
+}
+
+// validateRegisteredURI checks that a registered URI is absolute, has no fragment and uses https unless it points to
+// localhost or uses a custom scheme.
+func validateRegisteredURI(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	} else if !IsValidRedirectURI(u) {
+		return errors.Errorf("URI %s must be absolute and must not contain a fragment", raw)
+	} else if !IsRedirectURISecure(u) {
+		return errors.Errorf("URI %s must use https", raw)
+	}
+	return nil
+}
+
+func isAbsoluteURI(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.IsAbs()
+}
+
+func isHTTPSURI(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+// validateJSONWebKeySet checks that a JWK set contains at least one public key and no private keys.
+func validateJSONWebKeySet(raw json.RawMessage) error {
+	_, err := ParseJSONWebKeySet(raw)
+	return err
+}
@@ -0,0 +1,193 @@
+package fosite
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateClientMetadata(t *testing.T) {
+	for k, c := range []struct {
+		description string
+		metadata    *ClientMetadata
+		expectErr   error
+	}{
+		{
+			description: "should pass with the defaults",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}},
+		},
+		{
+			description: "should pass with a hybrid client",
+			metadata: &ClientMetadata{
+				RedirectURIs:  []string{"https://foo.example.com/cb", "http://localhost:3000/cb"},
+				GrantTypes:    []string{"authorization_code", "implicit", "refresh_token"},
+				ResponseTypes: []string{"code id_token", "code"},
+			},
+		},
+		{
+			description: "should pass with a machine client without redirect URIs",
+			metadata:    &ClientMetadata{GrantTypes: []string{"client_credentials"}, ResponseTypes: []string{"none"}},
+		},
+		{
+			description: "should fail because redirect URIs are missing",
+			metadata:    &ClientMetadata{},
+			expectErr:   ErrInvalidRedirectURI,
+		},
+		{
+			description: "should fail because the redirect URI is not secure",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"http://foo.example.com/cb"}},
+			expectErr:   ErrInvalidRedirectURI,
+		},
+		{
+			description: "should fail because the redirect URI contains a fragment",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb#foo"}},
+			expectErr:   ErrInvalidRedirectURI,
+		},
+		{
+			description: "should fail because response type token requires the implicit grant",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, ResponseTypes: []string{"code", "token"}},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the implicit grant requires response type token or id_token",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, GrantTypes: []string{"authorization_code", "implicit"}},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the grant type is unknown",
+			metadata:    &ClientMetadata{GrantTypes: []string{"foo"}, ResponseTypes: []string{"none"}},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should pass with an extension grant",
+			metadata:    &ClientMetadata{GrantTypes: []string{"urn:ietf:params:oauth:grant-type:jwt-bearer"}, ResponseTypes: []string{"none"}},
+		},
+		{
+			description: "should fail because the authentication method is not supported",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, TokenEndpointAuthMethod: "client_secret_jwt"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because public clients can not use client credentials",
+			metadata:    &ClientMetadata{GrantTypes: []string{"client_credentials"}, ResponseTypes: []string{"none"}, TokenEndpointAuthMethod: "none"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because jwks and jwks_uri are both set",
+			metadata: &ClientMetadata{
+				RedirectURIs:   []string{"https://foo.example.com/cb"},
+				JSONWebKeysURI: "https://foo.example.com/jwks.json",
+				JSONWebKeys:    json.RawMessage(`{"keys":[{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}]}`),
+			},
+			expectErr: ErrInvalidClientMetadata,
+		},
+		{
+			description: "should pass with a public key set",
+			metadata: &ClientMetadata{
+				RedirectURIs: []string{"https://foo.example.com/cb"},
+				JSONWebKeys:  json.RawMessage(`{"keys":[{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}]}`),
+			},
+		},
+		{
+			description: "should fail because the key set contains a private key",
+			metadata: &ClientMetadata{
+				RedirectURIs: []string{"https://foo.example.com/cb"},
+				JSONWebKeys:  json.RawMessage(`{"keys":[{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0","d":"jpsQnnGQmL-YBIffH1136cspYG6-0iY7X1fCE9-E9LI"}]}`),
+			},
+			expectErr: ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the key set is empty",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, JSONWebKeys: json.RawMessage(`{"keys":[]}`)},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because jwks_uri does not use https",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, JSONWebKeysURI: "http://foo.example.com/jwks.json"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because private_key_jwt requires keys",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, TokenEndpointAuthMethod: "private_key_jwt"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the subject type is unknown",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, SubjectType: "foo"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the post logout redirect URI is not secure",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, PostLogoutRedirectURIs: []string{"http://foo.example.com/"}},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should pass with OpenID Connect metadata",
+			metadata: &ClientMetadata{
+				RedirectURIs:    []string{"https://foo.example.com/cb"},
+				ApplicationType: "native",
+				ClientName:      "Foo",
+				LogoURI:         "https://foo.example.com/logo.png",
+				RequestURIs:     []string{"https://foo.example.com/request"},
+				DefaultMaxAge:   3600,
+			},
+		},
+		{
+			description: "should fail because the application type is unknown",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, ApplicationType: "desktop"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the ID token signing algorithm is not supported",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, IDTokenSignedResponseAlgorithm: "HS256"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because ID token encryption is not supported",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, IDTokenEncryptedResponseAlgorithm: "RSA-OAEP"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the logo URI does not use https",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, LogoURI: "http://foo.example.com/logo.png"},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because default_max_age is negative",
+			metadata:    &ClientMetadata{RedirectURIs: []string{"https://foo.example.com/cb"}, DefaultMaxAge: -1},
+			expectErr:   ErrInvalidClientMetadata,
+		},
+	} {
+		err := ValidateClientMetadata(c.metadata, append(DefaultTokenEndpointAuthMethods, "private_key_jwt"))
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+}
+
+func TestClientMetadataRoundTrip(t *testing.T) {
+	metadata := &ClientMetadata{
+		RedirectURIs:            []string{"https://foo.example.com/cb"},
+		TokenEndpointAuthMethod: "none",
+		GrantTypes:              []string{"authorization_code", "refresh_token"},
+		ResponseTypes:           []string{"code"},
+		Scope:                   "openid offline",
+		SubjectType:             SubjectTypePairwise,
+		PostLogoutRedirectURIs:  []string{"https://foo.example.com/logout"},
+
+		ApplicationType:                "native",
+		ClientName:                     "Foo",
+		LogoURI:                        "https://foo.example.com/logo.png",
+		Contacts:                       []string{"admin@foo.example.com"},
+		IDTokenSignedResponseAlgorithm: "RS256",
+		DefaultMaxAge:                  3600,
+		RequireAuthTime:                true,
+	}
+
+	client := NewClientFromMetadata("foo", metadata)
+	assert.True(t, client.IsPublic())
+	assert.Equal(t, Arguments{"openid", "offline"}, client.GetScopes())
+	assert.Equal(t, "native", client.GetApplicationType())
+	assert.Equal(t, int64(3600), client.GetDefaultMaxAge())
+	assert.Equal(t, metadata, GetClientMetadata(client))
+}
@@ -0,0 +1,201 @@
+package fosite
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// OpenIDConnectClient is an optional extension of Client exposing the client metadata defined in
+// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+//
+// Handlers detect it using a type assertion, so clients not implementing it keep working with the defaults.
+type OpenIDConnectClient interface {
+	// GetApplicationType returns either "web" or "native". Defaults to "web".
+	GetApplicationType() string
+
+	// GetClientName, GetClientURI, GetLogoURI, GetPolicyURI, GetTermsOfServiceURI and GetContacts return the data
+	// displayed to the end-user, e.g. on consent screens.
+	GetClientName() string
+	GetClientURI() string
+	GetLogoURI() string
+	GetPolicyURI() string
+	GetTermsOfServiceURI() string
+	GetContacts() []string
+
+	// GetTokenEndpointAuthMethod returns the registered client authentication method, e.g. client_secret_basic.
+	GetTokenEndpointAuthMethod() string
+
+	// GetTokenEndpointAuthSigningAlgorithm returns the algorithm of JWTs used to authenticate the client.
+	GetTokenEndpointAuthSigningAlgorithm() string
+
+	// GetJSONWebKeysURI and GetJSONWebKeys return the client's public keys. At most one of them is set.
+	GetJSONWebKeysURI() string
+	GetJSONWebKeys() json.RawMessage
+
+	// GetIDTokenSignedResponseAlgorithm returns the algorithm ID tokens must be signed with. Defaults to RS256.
+	GetIDTokenSignedResponseAlgorithm() string
+	GetIDTokenEncryptedResponseAlgorithm() string
+	GetIDTokenEncryptedResponseEncryption() string
+
+	// GetUserInfoSignedResponseAlgorithm returns the algorithm UserInfo responses must be signed with, or an empty
+	// string if they are returned as plain JSON.
+	GetUserInfoSignedResponseAlgorithm() string
+	GetUserInfoEncryptedResponseAlgorithm() string
+	GetUserInfoEncryptedResponseEncryption() string
+
+	// GetRequestObjectSigningAlgorithm returns the algorithm request objects must be signed with.
+	GetRequestObjectSigningAlgorithm() string
+
+	// GetRequestURIs returns the pre-registered request_uri values.
+	GetRequestURIs() []string
+
+	// GetDefaultMaxAge returns the default maximum authentication age in seconds, or zero if there is none.
+	GetDefaultMaxAge() int64
+
+	// IsAuthTimeRequired returns true if ID tokens must contain the auth_time claim.
+	IsAuthTimeRequired() bool
+
+	// GetDefaultACRValues returns the default requested Authentication Context Class Reference values.
+	GetDefaultACRValues() []string
+
+	// GetInitiateLoginURI returns the URI a third party can use to initiate a login by the client.
+	GetInitiateLoginURI() string
+
+	Client
+}
+
+// DefaultOpenIDConnectClient is a DefaultClient with the OpenID Connect client metadata.
+type DefaultOpenIDConnectClient struct {
+	*DefaultClient
+
+	ApplicationType                     string   `json:"application_type,omitempty"`
+	ClientName                          string   `json:"client_name,omitempty"`
+	ClientURI                           string   `json:"client_uri,omitempty"`
+	LogoURI                             string   `json:"logo_uri,omitempty"`
+	PolicyURI                           string   `json:"policy_uri,omitempty"`
+	TermsOfServiceURI                   string   `json:"tos_uri,omitempty"`
+	Contacts                            []string `json:"contacts,omitempty"`
+	TokenEndpointAuthSigningAlgorithm   string   `json:"token_endpoint_auth_signing_alg,omitempty"`
+	IDTokenSignedResponseAlgorithm      string   `json:"id_token_signed_response_alg,omitempty"`
+	IDTokenEncryptedResponseAlgorithm   string   `json:"id_token_encrypted_response_alg,omitempty"`
+	IDTokenEncryptedResponseEncryption  string   `json:"id_token_encrypted_response_enc,omitempty"`
+	UserInfoSignedResponseAlgorithm     string   `json:"userinfo_signed_response_alg,omitempty"`
+	UserInfoEncryptedResponseAlgorithm  string   `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserInfoEncryptedResponseEncryption string   `json:"userinfo_encrypted_response_enc,omitempty"`
+	RequestObjectSigningAlgorithm       string   `json:"request_object_signing_alg,omitempty"`
+	RequestURIs                         []string `json:"request_uris,omitempty"`
+	DefaultMaxAge                       int64    `json:"default_max_age,omitempty"`
+	RequireAuthTime                     bool     `json:"require_auth_time,omitempty"`
+	DefaultACRValues                    []string `json:"default_acr_values,omitempty"`
+	InitiateLoginURI                    string   `json:"initiate_login_uri,omitempty"`
+}
+
+func (c *DefaultOpenIDConnectClient) GetApplicationType() string {
+	if c.ApplicationType == "" {
+		return "web"
+	}
+	return c.ApplicationType
+}
+
+func (c *DefaultOpenIDConnectClient) GetClientName() string {
+	return c.ClientName
+}
+
+func (c *DefaultOpenIDConnectClient) GetClientURI() string {
+	return c.ClientURI
+}
+
+func (c *DefaultOpenIDConnectClient) GetLogoURI() string {
+	return c.LogoURI
+}
+
+func (c *DefaultOpenIDConnectClient) GetPolicyURI() string {
+	return c.PolicyURI
+}
+
+func (c *DefaultOpenIDConnectClient) GetTermsOfServiceURI() string {
+	return c.TermsOfServiceURI
+}
+
+func (c *DefaultOpenIDConnectClient) GetContacts() []string {
+	return c.Contacts
+}
+
+func (c *DefaultOpenIDConnectClient) GetTokenEndpointAuthSigningAlgorithm() string {
+	return c.TokenEndpointAuthSigningAlgorithm
+}
+
+func (c *DefaultOpenIDConnectClient) GetIDTokenSignedResponseAlgorithm() string {
+	if c.IDTokenSignedResponseAlgorithm == "" {
+		return "RS256"
+	}
+	return c.IDTokenSignedResponseAlgorithm
+}
+
+func (c *DefaultOpenIDConnectClient) GetIDTokenEncryptedResponseAlgorithm() string {
+	return c.IDTokenEncryptedResponseAlgorithm
+}
+
+func (c *DefaultOpenIDConnectClient) GetIDTokenEncryptedResponseEncryption() string {
+	return c.IDTokenEncryptedResponseEncryption
+}
+
+func (c *DefaultOpenIDConnectClient) GetUserInfoSignedResponseAlgorithm() string {
+	return c.UserInfoSignedResponseAlgorithm
+}
+
+func (c *DefaultOpenIDConnectClient) GetUserInfoEncryptedResponseAlgorithm() string {
+	return c.UserInfoEncryptedResponseAlgorithm
+}
+
+func (c *DefaultOpenIDConnectClient) GetUserInfoEncryptedResponseEncryption() string {
+	return c.UserInfoEncryptedResponseEncryption
+}
+
+func (c *DefaultOpenIDConnectClient) GetRequestObjectSigningAlgorithm() string {
+	return c.RequestObjectSigningAlgorithm
+}
+
+func (c *DefaultOpenIDConnectClient) GetRequestURIs() []string {
+	return c.RequestURIs
+}
+
+func (c *DefaultOpenIDConnectClient) GetDefaultMaxAge() int64 {
+	return c.DefaultMaxAge
+}
+
+func (c *DefaultOpenIDConnectClient) IsAuthTimeRequired() bool {
+	return c.RequireAuthTime
+}
+
+func (c *DefaultOpenIDConnectClient) GetDefaultACRValues() []string {
+	return c.DefaultACRValues
+}
+
+func (c *DefaultOpenIDConnectClient) GetInitiateLoginURI() string {
+	return c.InitiateLoginURI
+}
+
+// GetTokenEndpointAuthMethod returns the client authentication method of a client. Clients not implementing
+// OpenIDConnectClient use none if they are public and client_secret_basic otherwise.
+func GetTokenEndpointAuthMethod(c Client) string {
+	if oc, ok := c.(OpenIDConnectClient); ok && oc.GetTokenEndpointAuthMethod() != "" {
+		return oc.GetTokenEndpointAuthMethod()
+	} else if c.IsPublic() {
+		return "none"
+	}
+	return "client_secret_basic"
+}
+
+// checkClientSecretBasic returns ErrInvalidClient if a client implementing OpenIDConnectClient registered an
+// authentication method other than client_secret_basic, which is the only method the endpoints support for
+// confidential clients.
+func checkClientSecretBasic(c Client) error {
+	if oc, ok := c.(OpenIDConnectClient); ok {
+		if method := oc.GetTokenEndpointAuthMethod(); method != "" && method != "client_secret_basic" {
+			return errors.Wrapf(ErrInvalidClient, "The client registered the authentication method %s, but used client_secret_basic", method)
+		}
+	}
+	return nil
+}
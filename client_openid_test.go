@@ -0,0 +1,48 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultOpenIDConnectClient(t *testing.T) {
+	c := &DefaultOpenIDConnectClient{DefaultClient: &DefaultClient{ID: "foo"}}
+	assert.Equal(t, "web", c.GetApplicationType())
+	assert.Equal(t, "RS256", c.GetIDTokenSignedResponseAlgorithm())
+	assert.Equal(t, "client_secret_basic", c.GetTokenEndpointAuthMethod())
+
+	c.ApplicationType = "native"
+	c.IDTokenSignedResponseAlgorithm = "ES256"
+	assert.Equal(t, "native", c.GetApplicationType())
+	assert.Equal(t, "ES256", c.GetIDTokenSignedResponseAlgorithm())
+}
+
+func TestGetTokenEndpointAuthMethod(t *testing.T) {
+	for k, c := range []struct {
+		client Client
+		expect string
+	}{
+		{client: &DefaultClient{}, expect: "client_secret_basic"},
+		{client: &DefaultClient{Public: true}, expect: "none"},
+		{client: &DefaultOpenIDConnectClient{DefaultClient: &DefaultClient{TokenEndpointAuthMethod: "private_key_jwt"}}, expect: "private_key_jwt"},
+	} {
+		assert.Equal(t, c.expect, GetTokenEndpointAuthMethod(c.client), "%d", k)
+	}
+}
+
+func TestCheckClientSecretBasic(t *testing.T) {
+	for k, c := range []struct {
+		client    Client
+		expectErr error
+	}{
+		{client: &DefaultClient{TokenEndpointAuthMethod: "private_key_jwt"}},
+		{client: &DefaultOpenIDConnectClient{DefaultClient: &DefaultClient{}}},
+		{client: &DefaultOpenIDConnectClient{DefaultClient: &DefaultClient{TokenEndpointAuthMethod: "client_secret_basic"}}},
+		{client: &DefaultOpenIDConnectClient{DefaultClient: &DefaultClient{TokenEndpointAuthMethod: "private_key_jwt"}}, expectErr: ErrInvalidClient},
+	} {
+		err := checkClientSecretBasic(c.client)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s", k, err)
+	}
+}
@@ -0,0 +1,457 @@
+package fosite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+)
+
+// InitialAccessTokenValidator authorizes dynamic client registration requests by validating the initial access token
+// sent as a bearer token, see https://tools.ietf.org/html/rfc7591#section-3. It returns the privileged grant types,
+// i.e. password and client_credentials, that clients registered with the token may use.
+type InitialAccessTokenValidator func(ctx context.Context, token string) (grantTypes []string, err error)
+
+// privilegedGrantTypes are the grant types which let a client obtain tokens without the resource owner's consent.
+// They can only be registered if the initial access token authorizes them.
+var privilegedGrantTypes = []string{"password", "client_credentials"}
+
+// ClientTokenRevoker revokes all tokens issued to a client, e.g. OAuth2Provider.
+type ClientTokenRevoker interface {
+	RevokeTokensByClient(ctx context.Context, clientID string) error
+}
+
+// ClientRegistrationResponse is the client information response defined in
+// https://tools.ietf.org/html/rfc7591#section-3.2.1 and https://tools.ietf.org/html/rfc7592#section-3
+type ClientRegistrationResponse struct {
+	ClientID                string `json:"client_id"`
+	ClientSecret            string `json:"client_secret,omitempty"`
+	ClientIDIssuedAt        int64  `json:"client_id_issued_at,omitempty"`
+	ClientSecretExpiresAt   *int64 `json:"client_secret_expires_at,omitempty"`
+	RegistrationAccessToken string `json:"registration_access_token"`
+	RegistrationClientURI   string `json:"registration_client_uri"`
+
+	*ClientMetadata
+}
+
+// ClientRegistrationHandler implements the dynamic client registration endpoint (RFC 7591) and the client
+// configuration endpoint (RFC 7592). Clients are persisted as *DefaultOpenIDConnectClient.
+type ClientRegistrationHandler struct {
+	// Store persists the registered clients.
+	Store WritableClientManager
+
+	// Hasher hashes client secrets and registration access tokens.
+	Hasher Hasher
+
+	// RegistrationEndpoint is the absolute URL of the registration endpoint. The client configuration endpoint of a
+	// client is the registration endpoint followed by the client id, see GetRegistrationClientURI.
+	RegistrationEndpoint string
+
+	// InitialAccessTokenValidator, if set, is required to authorize registration requests. If nil, anyone may
+	// register clients, but not with the grant types password and client_credentials.
+	InitialAccessTokenValidator InitialAccessTokenValidator
+
+	// ScopeRegistry, if set, restricts the scopes clients may register to the registered scopes.
+	ScopeRegistry ScopeRegistry
+
+	// AllowedScopes, if set, restricts the scopes clients may register.
+	AllowedScopes []string
+
+	// AllowedAudiences are the audiences clients may register. If empty, clients can not register audiences.
+	AllowedAudiences []string
+
+	// AllowedGrantTypes, if set, restricts the grant types clients may register. The grant types password and
+	// client_credentials must additionally be authorized by the initial access token.
+	AllowedGrantTypes []string
+
+	// ClientSecretLifespan is the lifespan of generated client secrets. Zero means they never expire.
+	ClientSecretLifespan time.Duration
+
+	// TokenEndpointAuthMethods are the client authentication methods clients may register. Defaults to
+	// DefaultTokenEndpointAuthMethods.
+	TokenEndpointAuthMethods []string
+
+	// TokenRevoker, if set, revokes all tokens issued to a client when the client is deleted.
+	TokenRevoker ClientTokenRevoker
+
+	// SoftwareStatementStrategy verifies software statements. If nil, requests containing a software statement are
+	// rejected.
+	SoftwareStatementStrategy *SoftwareStatementStrategy
+
+	// SoftwareStatementPolicy decides which clients must send a software statement. If nil, software statements are
+	// optional.
+	SoftwareStatementPolicy SoftwareStatementPolicy
+}
+
+// RegisterClient handles a client registration request as defined in https://tools.ietf.org/html/rfc7591#section-3.1
+//
+//   To register, the client or developer sends an HTTP POST to the client
+//   registration endpoint with a content type of "application/json".
+//
+// A client secret is generated unless the client registered the authentication method none. The secret and the
+// registration access token are only returned in this response, the store only keeps their hashes.
+func (h *ClientRegistrationHandler) RegisterClient(ctx context.Context, r *http.Request) (*ClientRegistrationResponse, error) {
+	if r.Method != "POST" {
+		return nil, errors.Wrap(ErrInvalidRequest, "HTTP method is not POST")
+	}
+
+	var authorizedGrantTypes []string
+	if h.InitialAccessTokenValidator != nil {
+		token := AccessTokenFromRequest(r)
+		if token == "" {
+			return nil, errors.Wrap(ErrRequestUnauthorized, "Initial access token is missing")
+		}
+
+		var err error
+		if authorizedGrantTypes, err = h.InitialAccessTokenValidator(ctx, token); err != nil {
+			return nil, errors.Wrap(ErrRequestUnauthorized, err.Error())
+		}
+	}
+
+	metadata, _, err := h.decodeClientMetadata(r)
+	if err != nil {
+		return nil, err
+	} else if err := h.validateRegistrationPolicy(metadata, authorizedGrantTypes); err != nil {
+		return nil, err
+	}
+
+	client := NewClientFromMetadata(uuid.New(), metadata)
+	response, err := h.issueCredentials(client, !client.IsPublic())
+	if err != nil {
+		return nil, err
+	}
+	response.ClientIDIssuedAt = time.Now().Unix()
+
+	if err := h.Store.CreateClient(ctx, client); err != nil {
+		return nil, errors.Wrap(ErrServerError, err.Error())
+	}
+
+	return response, nil
+}
+
+// GetClientConfiguration handles a client read request as defined in https://tools.ietf.org/html/rfc7592#section-2.1
+//
+// The registration access token is rotated, so the client must use the token returned in the response for the next
+// request.
+func (h *ClientRegistrationHandler) GetClientConfiguration(ctx context.Context, r *http.Request, clientID string) (*ClientRegistrationResponse, error) {
+	if r.Method != "GET" {
+		return nil, errors.Wrap(ErrInvalidRequest, "HTTP method is not GET")
+	}
+
+	client, err := h.authenticateRegistrationAccessToken(ctx, r, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.issueCredentials(client, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.Store.UpdateClient(ctx, client); err != nil {
+		return nil, errors.Wrap(ErrServerError, err.Error())
+	}
+
+	return response, nil
+}
+
+// UpdateClientConfiguration handles a client update request as defined in
+// https://tools.ietf.org/html/rfc7592#section-2.2
+//
+//   This request MUST include all client metadata fields as returned to
+//   the client from a previous registration, read, or update operation.
+//   [...] Omitted fields MUST be treated as null or empty values by the
+//   server, indicating the client's request to delete them from the
+//   client's registration.
+//
+// The client secret is kept unless the client switches from or to the authentication method none. The registration
+// access token is rotated.
+func (h *ClientRegistrationHandler) UpdateClientConfiguration(ctx context.Context, r *http.Request, clientID string) (*ClientRegistrationResponse, error) {
+	if r.Method != "PUT" {
+		return nil, errors.Wrap(ErrInvalidRequest, "HTTP method is not PUT")
+	}
+
+	current, err := h.authenticateRegistrationAccessToken(ctx, r, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, body, err := h.decodeClientMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Privileged grant types the client was registered with remain authorized.
+	if err := h.validateRegistrationPolicy(metadata, current.GetGrantTypes()); err != nil {
+		return nil, err
+	}
+
+	//   The client MUST NOT include the "registration_access_token",
+	//   "registration_client_uri", "client_secret_expires_at", or
+	//   "client_id_issued_at" fields described in Section 3.
+	for _, forbidden := range []string{"registration_access_token", "registration_client_uri", "client_secret_expires_at", "client_id_issued_at"} {
+		if _, ok := body[forbidden]; ok {
+			return nil, errors.Wrapf(ErrInvalidRequest, "Parameter %s must not be sent", forbidden)
+		}
+	}
+	if id, _ := body["client_id"].(string); id != clientID {
+		return nil, errors.Wrap(ErrInvalidRequest, "Parameter client_id does not match the client")
+	}
+	if secret, ok := body["client_secret"].(string); ok {
+		if err := CompareClientSecret(h.Hasher, current, []byte(secret)); err != nil {
+			return nil, errors.Wrap(ErrInvalidRequest, "Parameter client_secret does not match the client")
+		}
+	}
+
+	client := NewClientFromMetadata(clientID, metadata)
+	if !client.IsPublic() {
+		client.Secret = current.Secret
+		client.SecretExpiresAt = current.SecretExpiresAt
+		client.RotatedSecrets = current.RotatedSecrets
+	}
+
+	response, err := h.issueCredentials(client, !client.IsPublic() && len(GetClientSecrets(client, time.Now().UTC())) == 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.Store.UpdateClient(ctx, client); err != nil {
+		return nil, errors.Wrap(ErrServerError, err.Error())
+	}
+
+	return response, nil
+}
+
+// DeleteClientConfiguration handles a client delete request as defined in
+// https://tools.ietf.org/html/rfc7592#section-2.3
+//
+//   If a client has been successfully deprovisioned, the authorization
+//   server MUST respond with an HTTP 204 No Content message.
+//
+// If TokenRevoker is set, all tokens issued to the client are revoked.
+func (h *ClientRegistrationHandler) DeleteClientConfiguration(ctx context.Context, r *http.Request, clientID string) error {
+	if r.Method != "DELETE" {
+		return errors.Wrap(ErrInvalidRequest, "HTTP method is not DELETE")
+	}
+
+	if _, err := h.authenticateRegistrationAccessToken(ctx, r, clientID); err != nil {
+		return err
+	}
+
+	if h.TokenRevoker != nil {
+		if err := h.TokenRevoker.RevokeTokensByClient(ctx, clientID); err != nil {
+			return err
+		}
+	}
+
+	if err := h.Store.DeleteClient(ctx, clientID); err != nil {
+		return errors.Wrap(ErrServerError, err.Error())
+	}
+	return nil
+}
+
+// GetRegistrationClientURI returns the client configuration endpoint of a client.
+func (h *ClientRegistrationHandler) GetRegistrationClientURI(clientID string) string {
+	return strings.TrimRight(h.RegistrationEndpoint, "/") + "/" + url.PathEscape(clientID)
+}
+
+// WriteClientRegistrationResponse writes a client information response with the given status code, which is
+// http.StatusCreated for registration requests and http.StatusOK otherwise.
+func (h *ClientRegistrationHandler) WriteClientRegistrationResponse(rw http.ResponseWriter, status int, response *ClientRegistrationResponse) {
+	js, err := json.Marshal(response)
+	if err != nil {
+		h.WriteClientRegistrationError(rw, errors.Wrap(ErrServerError, err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+	rw.WriteHeader(status)
+	rw.Write(js)
+}
+
+// WriteClientRegistrationError writes an error response as defined in https://tools.ietf.org/html/rfc7591#section-3.2.2
+func (h *ClientRegistrationHandler) WriteClientRegistrationError(rw http.ResponseWriter, err error) {
+	rw.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+
+	rfcerr := ErrorToRFC6749Error(err)
+	js, err := json.Marshal(rfcerr)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(rfcerr.Code)
+	rw.Write(js)
+}
+
+// decodeClientMetadata decodes and validates the JSON client metadata of a request. If the request contains a
+// software statement, its claims take precedence over the submitted values. The submitted JSON object is returned as
+// well.
+func (h *ClientRegistrationHandler) decodeClientMetadata(r *http.Request) (*ClientMetadata, map[string]interface{}, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return nil, nil, errors.Wrap(ErrInvalidRequest, "Content type must be application/json")
+	}
+
+	var submitted map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&submitted); err != nil {
+		return nil, nil, errors.Wrap(ErrInvalidClientMetadata, err.Error())
+	}
+
+	values, unsigned, err := applySoftwareStatement(h.SoftwareStatementStrategy, submitted)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var metadata ClientMetadata
+	if err := decodeClientMetadataValues(values, &metadata); err != nil {
+		return nil, nil, err
+	}
+
+	authMethods := h.TokenEndpointAuthMethods
+	if len(authMethods) == 0 {
+		authMethods = DefaultTokenEndpointAuthMethods
+	}
+	if err := ValidateClientMetadata(&metadata, authMethods); err != nil {
+		return nil, nil, err
+	}
+
+	if h.SoftwareStatementPolicy != nil {
+		// Only the values asserted by the software statement are signed, a statement may be presented by multiple
+		// instances of the software and the other values can be chosen freely.
+		var unsignedMetadata ClientMetadata
+		if err := decodeClientMetadataValues(unsigned, &unsignedMetadata); err != nil {
+			return nil, nil, err
+		} else if h.SoftwareStatementPolicy.RequiresSoftwareStatement(&unsignedMetadata) {
+			return nil, nil, errors.Wrap(ErrInvalidSoftwareStatement, "A software statement asserting the scopes and grant types is required to register this client")
+		}
+	}
+	return &metadata, submitted, nil
+}
+
+// decodeClientMetadataValues decodes a JSON object into client metadata by round-tripping it through JSON.
+func decodeClientMetadataValues(values map[string]interface{}, metadata *ClientMetadata) error {
+	body, err := json.Marshal(values)
+	if err != nil {
+		return errors.Wrap(ErrServerError, err.Error())
+	} else if err := json.Unmarshal(body, metadata); err != nil {
+		return errors.Wrap(ErrInvalidClientMetadata, err.Error())
+	}
+	return nil
+}
+
+// validateRegistrationPolicy checks that the client metadata only contains the scopes, audiences and grant types
+// clients may register. The privileged grant types password and client_credentials must be contained in
+// authorizedGrantTypes.
+func (h *ClientRegistrationHandler) validateRegistrationPolicy(m *ClientMetadata, authorizedGrantTypes []string) error {
+	for _, scope := range strings.Split(m.Scope, " ") {
+		if scope == "" {
+			continue
+		} else if len(h.AllowedScopes) > 0 && !StringInSlice(scope, h.AllowedScopes) {
+			return errors.Wrapf(ErrInvalidClientMetadata, "Scope %s may not be registered", scope)
+		} else if _, ok := h.ScopeRegistry.Get(scope); h.ScopeRegistry != nil && !ok {
+			return errors.Wrapf(ErrInvalidClientMetadata, "Scope %s is not supported", scope)
+		}
+	}
+
+	for _, audience := range m.Audience {
+		if !StringInSlice(audience, h.AllowedAudiences) {
+			return errors.Wrapf(ErrInvalidClientMetadata, "Audience %s may not be registered", audience)
+		}
+	}
+
+	for _, grantType := range m.GrantTypes {
+		if len(h.AllowedGrantTypes) > 0 && !StringInSlice(grantType, h.AllowedGrantTypes) {
+			return errors.Wrapf(ErrInvalidClientMetadata, "Grant type %s may not be registered", grantType)
+		} else if StringInSlice(grantType, privilegedGrantTypes) && !StringInSlice(grantType, authorizedGrantTypes) {
+			return errors.Wrapf(ErrInvalidClientMetadata, "Grant type %s requires an initial access token authorizing it", grantType)
+		}
+	}
+	return nil
+}
+
+// authenticateRegistrationAccessToken loads a client and verifies the registration access token of the request.
+// Unknown clients are reported like invalid tokens, see https://tools.ietf.org/html/rfc7592#section-2.1
+func (h *ClientRegistrationHandler) authenticateRegistrationAccessToken(ctx context.Context, r *http.Request, clientID string) (*DefaultOpenIDConnectClient, error) {
+	token := AccessTokenFromRequest(r)
+	if token == "" {
+		return nil, errors.Wrap(ErrRequestUnauthorized, "Registration access token is missing")
+	}
+
+	c, err := h.Store.GetClient(ctx, clientID)
+	if errors.Cause(err) == ErrNotFound {
+		return nil, errors.Wrap(ErrRequestUnauthorized, "Registration access token is invalid")
+	} else if err != nil {
+		return nil, errors.Wrap(ErrServerError, err.Error())
+	}
+
+	var client *DefaultOpenIDConnectClient
+	switch v := c.(type) {
+	case *DefaultOpenIDConnectClient:
+		client = v
+	case *DefaultClient:
+		// Clients registered before the OpenID Connect metadata was stored.
+		client = &DefaultOpenIDConnectClient{DefaultClient: v}
+	}
+
+	if client == nil || client.DefaultClient == nil || len(client.RegistrationAccessToken) == 0 {
+		return nil, errors.Wrap(ErrRequestUnauthorized, "Client can not be managed using a registration access token")
+	} else if err := h.Hasher.Compare(client.RegistrationAccessToken, []byte(token)); err != nil {
+		return nil, errors.Wrap(ErrRequestUnauthorized, "Registration access token is invalid")
+	}
+	return client, nil
+}
+
+// issueCredentials generates a new registration access token and, if requested, a new client secret for the client
+// and returns the client information response containing them.
+func (h *ClientRegistrationHandler) issueCredentials(client *DefaultOpenIDConnectClient, withSecret bool) (*ClientRegistrationResponse, error) {
+	response := &ClientRegistrationResponse{
+		ClientID:              client.ID,
+		RegistrationClientURI: h.GetRegistrationClientURI(client.ID),
+		ClientMetadata:        GetClientMetadata(client),
+	}
+
+	token, err := generateClientCredential()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := h.Hasher.Hash([]byte(token))
+	if err != nil {
+		return nil, errors.Wrap(ErrServerError, err.Error())
+	}
+	client.RegistrationAccessToken = hash
+	response.RegistrationAccessToken = token
+
+	if withSecret {
+		secret, hash, err := GenerateClientSecret(h.Hasher)
+		if err != nil {
+			return nil, err
+		}
+		client.Secret = hash
+		client.SecretExpiresAt = time.Time{}
+		if h.ClientSecretLifespan > 0 {
+			client.SecretExpiresAt = time.Now().UTC().Add(h.ClientSecretLifespan).Round(time.Second)
+		}
+		response.ClientSecret = secret
+	}
+
+	if !client.IsPublic() {
+		// Zero means the secret never expires.
+		var expiresAt int64
+		if !client.SecretExpiresAt.IsZero() {
+			expiresAt = client.SecretExpiresAt.Unix()
+		}
+		response.ClientSecretExpiresAt = &expiresAt
+	}
+
+	return response, nil
+}
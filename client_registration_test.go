@@ -0,0 +1,258 @@
+package fosite_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/ory/fosite"
+	"github.com/ory/fosite/storage"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clientRevoker []string
+
+func (r *clientRevoker) RevokeTokensByClient(_ context.Context, clientID string) error {
+	*r = append(*r, clientID)
+	return nil
+}
+
+func newRegistrationRequest(method, body, token string) *http.Request {
+	r := httptest.NewRequest(method, "https://server.example.com/register", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestClientRegistration(t *testing.T) {
+	store := storage.NewMemoryStore()
+	revoker := &clientRevoker{}
+	hasher := &BCrypt{WorkFactor: 4}
+	h := &ClientRegistrationHandler{
+		Store:                store,
+		Hasher:               hasher,
+		RegistrationEndpoint: "https://server.example.com/register/",
+		TokenRevoker:         revoker,
+	}
+
+	res, err := h.RegisterClient(nil, newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"scope":"openid offline"}`, ""))
+	require.Nil(t, err, "%s", err)
+	assert.NotEmpty(t, res.ClientID)
+	assert.NotEmpty(t, res.ClientSecret)
+	assert.NotEmpty(t, res.RegistrationAccessToken)
+	assert.NotZero(t, res.ClientIDIssuedAt)
+	assert.Equal(t, int64(0), *res.ClientSecretExpiresAt)
+	assert.Equal(t, "https://server.example.com/register/"+res.ClientID, res.RegistrationClientURI)
+	assert.Equal(t, "client_secret_basic", res.TokenEndpointAuthMethod)
+	assert.Equal(t, []string{"authorization_code"}, res.GrantTypes)
+
+	client := store.OpenIDConnectClients[res.ClientID]
+	require.NotNil(t, client)
+	assert.Nil(t, hasher.Compare(client.GetHashedSecret(), []byte(res.ClientSecret)))
+	assert.Equal(t, Arguments{"openid", "offline"}, client.GetScopes())
+
+	rw := httptest.NewRecorder()
+	h.WriteClientRegistrationResponse(rw, http.StatusCreated, res)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	var body map[string]interface{}
+	require.Nil(t, json.Unmarshal(rw.Body.Bytes(), &body))
+	assert.Equal(t, res.ClientID, body["client_id"])
+	assert.Equal(t, float64(0), body["client_secret_expires_at"])
+	assert.Equal(t, []interface{}{"https://foo.example.com/cb"}, body["redirect_uris"])
+
+	_, err = h.GetClientConfiguration(nil, newRegistrationRequest("GET", "", "invalid"), res.ClientID)
+	assert.True(t, errors.Cause(err) == ErrRequestUnauthorized, "%s", err)
+	_, err = h.GetClientConfiguration(nil, newRegistrationRequest("GET", "", res.RegistrationAccessToken), "unknown")
+	assert.True(t, errors.Cause(err) == ErrRequestUnauthorized, "%s", err)
+
+	read, err := h.GetClientConfiguration(nil, newRegistrationRequest("GET", "", res.RegistrationAccessToken), res.ClientID)
+	require.Nil(t, err, "%s", err)
+	assert.Empty(t, read.ClientSecret)
+	assert.NotEqual(t, res.RegistrationAccessToken, read.RegistrationAccessToken)
+	assert.Equal(t, "openid offline", read.Scope)
+
+	// The registration access token was rotated.
+	_, err = h.GetClientConfiguration(nil, newRegistrationRequest("GET", "", res.RegistrationAccessToken), res.ClientID)
+	assert.True(t, errors.Cause(err) == ErrRequestUnauthorized, "%s", err)
+
+	_, err = h.UpdateClientConfiguration(nil, newRegistrationRequest("PUT", `{"client_id":"`+res.ClientID+`","redirect_uris":["https://foo.example.com/cb"],"registration_access_token":"foo"}`, read.RegistrationAccessToken), res.ClientID)
+	assert.True(t, errors.Cause(err) == ErrInvalidRequest, "%s", err)
+	_, err = h.UpdateClientConfiguration(nil, newRegistrationRequest("PUT", `{"client_id":"other","redirect_uris":["https://foo.example.com/cb"]}`, read.RegistrationAccessToken), res.ClientID)
+	assert.True(t, errors.Cause(err) == ErrInvalidRequest, "%s", err)
+	_, err = h.UpdateClientConfiguration(nil, newRegistrationRequest("PUT", `{"client_id":"`+res.ClientID+`","client_secret":"wrong","redirect_uris":["https://foo.example.com/cb"]}`, read.RegistrationAccessToken), res.ClientID)
+	assert.True(t, errors.Cause(err) == ErrInvalidRequest, "%s", err)
+	_, err = h.UpdateClientConfiguration(nil, newRegistrationRequest("PUT", `{"client_id":"`+res.ClientID+`","redirect_uris":["http://foo.example.com/cb"]}`, read.RegistrationAccessToken), res.ClientID)
+	assert.True(t, errors.Cause(err) == ErrInvalidRedirectURI, "%s", err)
+
+	updated, err := h.UpdateClientConfiguration(nil, newRegistrationRequest("PUT", `{"client_id":"`+res.ClientID+`","client_secret":"`+res.ClientSecret+`","redirect_uris":["https://bar.example.com/cb"]}`, read.RegistrationAccessToken), res.ClientID)
+	require.Nil(t, err, "%s", err)
+	assert.Empty(t, updated.ClientSecret)
+	assert.Equal(t, []string{"https://bar.example.com/cb"}, store.OpenIDConnectClients[res.ClientID].RedirectURIs)
+	assert.Empty(t, store.OpenIDConnectClients[res.ClientID].Scopes)
+	assert.Nil(t, hasher.Compare(store.OpenIDConnectClients[res.ClientID].GetHashedSecret(), []byte(res.ClientSecret)))
+
+	err = h.DeleteClientConfiguration(nil, newRegistrationRequest("DELETE", "", read.RegistrationAccessToken), res.ClientID)
+	assert.True(t, errors.Cause(err) == ErrRequestUnauthorized, "%s", err)
+	require.Nil(t, h.DeleteClientConfiguration(nil, newRegistrationRequest("DELETE", "", updated.RegistrationAccessToken), res.ClientID))
+	assert.Nil(t, store.OpenIDConnectClients[res.ClientID])
+	assert.Equal(t, []string{res.ClientID}, []string(*revoker))
+}
+
+func TestClientRegistrationErrors(t *testing.T) {
+	h := &ClientRegistrationHandler{
+		Store:                storage.NewMemoryStore(),
+		Hasher:               &BCrypt{WorkFactor: 4},
+		RegistrationEndpoint: "https://server.example.com/register",
+		InitialAccessTokenValidator: func(_ context.Context, token string) ([]string, error) {
+			if token != "initial-token" {
+				return nil, errors.New("invalid initial access token")
+			}
+			return nil, nil
+		},
+	}
+
+	for k, c := range []struct {
+		description string
+		request     *http.Request
+		expectErr   error
+	}{
+		{
+			description: "should fail because the method is not POST",
+			request:     newRegistrationRequest("GET", `{}`, "initial-token"),
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail because the initial access token is missing",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"]}`, ""),
+			expectErr:   ErrRequestUnauthorized,
+		},
+		{
+			description: "should fail because the initial access token is invalid",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"]}`, "foo"),
+			expectErr:   ErrRequestUnauthorized,
+		},
+		{
+			description: "should fail because the body is not JSON",
+			request:     newRegistrationRequest("POST", `foo`, "initial-token"),
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the metadata is inconsistent",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"response_types":["token"]}`, "initial-token"),
+			expectErr:   ErrInvalidClientMetadata,
+		},
+	} {
+		_, err := h.RegisterClient(nil, c.request)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+
+	res, err := h.RegisterClient(nil, newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"token_endpoint_auth_method":"none"}`, "initial-token"))
+	require.Nil(t, err, "%s", err)
+	assert.Empty(t, res.ClientSecret)
+	assert.Nil(t, res.ClientSecretExpiresAt)
+
+	rw := httptest.NewRecorder()
+	h.WriteClientRegistrationError(rw, errors.Wrap(ErrInvalidRedirectURI, "foo"))
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Contains(t, rw.Body.String(), `"error":"invalid_redirect_uri"`)
+}
+
+func TestClientRegistrationPolicy(t *testing.T) {
+	h := &ClientRegistrationHandler{
+		Store:                storage.NewMemoryStore(),
+		Hasher:               &BCrypt{WorkFactor: 4},
+		RegistrationEndpoint: "https://server.example.com/register",
+		ScopeRegistry:        ScopeRegistry{"openid": {}, "photos": {}, "admin": {}},
+		AllowedScopes:        []string{"openid", "photos.read"},
+		AllowedAudiences:     []string{"https://api.example.com"},
+		AllowedGrantTypes:    []string{"authorization_code", "refresh_token", "client_credentials"},
+		InitialAccessTokenValidator: func(_ context.Context, token string) ([]string, error) {
+			switch token {
+			case "initial-token":
+				return nil, nil
+			case "privileged-token":
+				return []string{"client_credentials"}, nil
+			}
+			return nil, errors.New("invalid initial access token")
+		},
+	}
+
+	for k, c := range []struct {
+		description string
+		request     *http.Request
+		expectErr   error
+	}{
+		{
+			description: "should pass",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"scope":"openid photos.read","audience":["https://api.example.com"]}`, "initial-token"),
+		},
+		{
+			description: "should fail because the scope is not allowed",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"scope":"openid admin"}`, "initial-token"),
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the audience is not allowed",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"audience":["https://admin.example.com"]}`, "initial-token"),
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the grant type is not allowed",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"grant_types":["authorization_code","implicit"],"response_types":["code","token"]}`, "initial-token"),
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should fail because the initial access token does not authorize client_credentials",
+			request:     newRegistrationRequest("POST", `{"grant_types":["client_credentials"],"response_types":["none"]}`, "initial-token"),
+			expectErr:   ErrInvalidClientMetadata,
+		},
+		{
+			description: "should pass because the initial access token authorizes client_credentials",
+			request:     newRegistrationRequest("POST", `{"grant_types":["client_credentials"],"response_types":["none"]}`, "privileged-token"),
+		},
+	} {
+		_, err := h.RegisterClient(nil, c.request)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+
+	h.AllowedScopes = nil
+	_, err := h.RegisterClient(nil, newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"scope":"unknown"}`, "initial-token"))
+	assert.True(t, errors.Cause(err) == ErrInvalidClientMetadata, "%s", err)
+
+	// Without an initial access token validator, privileged grant types can not be registered at all.
+	h.InitialAccessTokenValidator = nil
+	_, err = h.RegisterClient(nil, newRegistrationRequest("POST", `{"grant_types":["client_credentials"],"response_types":["none"]}`, ""))
+	assert.True(t, errors.Cause(err) == ErrInvalidClientMetadata, "%s", err)
+	_, err = h.RegisterClient(nil, newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"grant_types":["authorization_code","password"]}`, ""))
+	assert.True(t, errors.Cause(err) == ErrInvalidClientMetadata, "%s", err)
+}
+
+func TestClientRegistrationSecretLifespan(t *testing.T) {
+	store := storage.NewMemoryStore()
+	h := &ClientRegistrationHandler{
+		Store:                store,
+		Hasher:               &BCrypt{WorkFactor: 4},
+		RegistrationEndpoint: "https://server.example.com/register/",
+		ClientSecretLifespan: time.Hour,
+	}
+
+	res, err := h.RegisterClient(nil, newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"]}`, ""))
+	require.Nil(t, err, "%s", err)
+	require.NotNil(t, res.ClientSecretExpiresAt)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), *res.ClientSecretExpiresAt, 5)
+
+	client := store.OpenIDConnectClients[res.ClientID]
+	assert.Equal(t, *res.ClientSecretExpiresAt, client.SecretExpiresAt.Unix())
+	assert.Nil(t, CompareClientSecret(h.Hasher, client, []byte(res.ClientSecret)))
+
+	client.SecretExpiresAt = time.Now().Add(-time.Minute)
+	assert.NotNil(t, CompareClientSecret(h.Hasher, client, []byte(res.ClientSecret)))
+}
@@ -0,0 +1,91 @@
+package fosite
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ClientSecret is a hashed client secret that is valid until it expires.
+type ClientSecret struct {
+	// Hash is the hashed secret as it is stored in the store.
+	Hash []byte `json:"hash"`
+
+	// ExpiresAt is the time the secret expires at. The zero value means the secret never expires.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// IsExpired returns true if the secret expired before the given time.
+func (s ClientSecret) IsExpired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && now.After(s.ExpiresAt)
+}
+
+// ClientWithSecretRotation is an optional extension of Client for clients with several active secrets, which
+// allows rotating a secret without an outage: the new secret is added before the old one expires.
+type ClientWithSecretRotation interface {
+	// GetHashedSecrets returns all secrets of the client, including expired ones.
+	GetHashedSecrets() []ClientSecret
+
+	Client
+}
+
+// GetClientSecrets returns the secrets of a client which are valid at the given time. Clients not implementing
+// ClientWithSecretRotation have a single secret which never expires.
+func GetClientSecrets(c Client, now time.Time) []ClientSecret {
+	rc, ok := c.(ClientWithSecretRotation)
+	if !ok {
+		return []ClientSecret{{Hash: c.GetHashedSecret()}}
+	}
+
+	var valid []ClientSecret
+	for _, secret := range rc.GetHashedSecrets() {
+		if len(secret.Hash) > 0 && !secret.IsExpired(now) {
+			valid = append(valid, secret)
+		}
+	}
+	return valid
+}
+
+// CompareClientSecret compares the secret to every secret of the client that has not expired yet and returns nil if
+// any of them matches.
+func CompareClientSecret(hasher Hasher, c Client, secret []byte) error {
+	secrets := GetClientSecrets(c, time.Now().UTC())
+	if len(secrets) == 0 {
+		return errors.New("The client has no valid secret")
+	}
+
+	var err error
+	for _, s := range secrets {
+		if err = hasher.Compare(s.Hash, secret); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// GenerateClientSecret returns a new client secret with 256 bits of entropy, encoded as URL-safe base64, together
+// with its hash.
+func GenerateClientSecret(hasher Hasher) (secret string, hash []byte, err error) {
+	secret, err = generateClientCredential()
+	if err != nil {
+		return "", nil, err
+	}
+
+	hash, err = hasher.Hash([]byte(secret))
+	if err != nil {
+		return "", nil, errors.Wrap(ErrServerError, err.Error())
+	}
+	return secret, hash, nil
+}
+
+// generateClientCredential returns 32 random bytes encoded as URL-safe base64.
+func generateClientCredential() (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", errors.Wrap(ErrServerError, err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
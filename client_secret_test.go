@@ -0,0 +1,74 @@
+package fosite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareClientSecret(t *testing.T) {
+	hasher := &BCrypt{WorkFactor: 4}
+	current, currentHash, err := GenerateClientSecret(hasher)
+	require.Nil(t, err)
+	previous, previousHash, err := GenerateClientSecret(hasher)
+	require.Nil(t, err)
+	expired, expiredHash, err := GenerateClientSecret(hasher)
+	require.Nil(t, err)
+	assert.NotEqual(t, current, previous)
+	assert.Len(t, current, 43)
+
+	client := &DefaultClient{
+		ID:     "foo",
+		Secret: currentHash,
+		RotatedSecrets: []ClientSecret{
+			{Hash: previousHash, ExpiresAt: time.Now().Add(time.Hour)},
+			{Hash: expiredHash, ExpiresAt: time.Now().Add(-time.Hour)},
+		},
+	}
+
+	assert.Nil(t, CompareClientSecret(hasher, client, []byte(current)))
+	assert.Nil(t, CompareClientSecret(hasher, client, []byte(previous)))
+	assert.NotNil(t, CompareClientSecret(hasher, client, []byte(expired)))
+	assert.NotNil(t, CompareClientSecret(hasher, client, []byte("foo")))
+
+	client.SecretExpiresAt = time.Now().Add(-time.Minute)
+	assert.NotNil(t, CompareClientSecret(hasher, client, []byte(current)))
+	assert.Nil(t, CompareClientSecret(hasher, client, []byte(previous)))
+
+	client.RotatedSecrets = nil
+	assert.NotNil(t, CompareClientSecret(hasher, client, []byte(previous)))
+}
+
+func TestDefaultClientRotateSecret(t *testing.T) {
+	hasher := &BCrypt{WorkFactor: 4}
+	old, oldHash, err := GenerateClientSecret(hasher)
+	require.Nil(t, err)
+	secret, hash, err := GenerateClientSecret(hasher)
+	require.Nil(t, err)
+
+	client := &DefaultClient{
+		ID:             "foo",
+		Secret:         oldHash,
+		RotatedSecrets: []ClientSecret{{Hash: []byte("expired"), ExpiresAt: time.Now().Add(-time.Hour)}},
+	}
+	gracePeriod := time.Now().Add(time.Hour)
+	client.RotateSecret(hash, time.Time{}, gracePeriod)
+
+	assert.Equal(t, hash, client.GetHashedSecret())
+	require.Len(t, client.RotatedSecrets, 1)
+	assert.Equal(t, gracePeriod, client.RotatedSecrets[0].ExpiresAt)
+	assert.Len(t, client.GetHashedSecrets(), 2)
+	assert.Nil(t, CompareClientSecret(hasher, client, []byte(secret)))
+	assert.Nil(t, CompareClientSecret(hasher, client, []byte(old)))
+
+	// A zero grace period expires the previous secret immediately.
+	newer, newerHash, err := GenerateClientSecret(hasher)
+	require.Nil(t, err)
+	client.RotateSecret(newerHash, time.Time{}, time.Time{})
+	assert.Len(t, client.RotatedSecrets, 1)
+	assert.Nil(t, CompareClientSecret(hasher, client, []byte(newer)))
+	assert.NotNil(t, CompareClientSecret(hasher, client, []byte(secret)))
+	assert.Nil(t, CompareClientSecret(hasher, client, []byte(old)))
+}
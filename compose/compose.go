@@ -41,7 +41,12 @@ func Compose(config *Config, storage interface{}, strategy interface{}, hasher f
 		TokenIntrospectionHandlers: fosite.TokenIntrospectionHandlers{},
 		RevocationHandlers:         fosite.RevocationHandlers{},
 		Hasher:                     hasher,
-		ScopeStrategy:              fosite.HierarchicScopeStrategy,
+		ScopeStrategy:              config.GetScopeStrategy(),
+		AudienceMatchingStrategy:   fosite.DefaultAudienceMatchingStrategy,
+		SubjectIdentifierStrategy:  config.SubjectIdentifierStrategy,
+		ScopeRegistry:              config.ScopeRegistry,
+		RedirectURIMatcher:         config.RedirectURIMatcher,
+		RedirectURIMatchers:        config.RedirectURIMatchers,
 	}
 
 	for _, factory := range factories {
@@ -70,7 +75,7 @@ func ComposeAllEnabled(config *Config, storage interface{}, secret []byte, key *
 		storage,
 		&CommonStrategy{
 			CoreStrategy:               NewOAuth2HMACStrategy(config, secret),
-			OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(key),
+			OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(config, key),
 		},
 		nil,
 
@@ -1,7 +1,6 @@
 package compose
 
 import (
-	"github.com/ory/fosite"
 	"github.com/ory/fosite/handler/oauth2"
 )
 
@@ -15,7 +14,11 @@ func OAuth2AuthorizeExplicitFactory(config *Config, storage interface{}, strateg
 		AuthorizeCodeGrantStorage: storage.(oauth2.AuthorizeCodeGrantStorage),
 		AuthCodeLifespan:          config.GetAuthorizeCodeLifespan(),
 		AccessTokenLifespan:       config.GetAccessTokenLifespan(),
-		ScopeStrategy:             fosite.HierarchicScopeStrategy,
+		RefreshTokenLifespan:      config.GetRefreshTokenLifespan(),
+		RefreshTokenMaxLifespan:   config.GetRefreshTokenMaxLifespan(),
+		ScopeStrategy:             config.GetScopeStrategy(),
+		RedirectURIMatcher:        config.RedirectURIMatcher,
+		RedirectURIMatchers:       config.RedirectURIMatchers,
 	}
 }
 
@@ -28,7 +31,7 @@ func OAuth2ClientCredentialsGrantFactory(config *Config, storage interface{}, st
 			AccessTokenStorage:  storage.(oauth2.AccessTokenStorage),
 			AccessTokenLifespan: config.GetAccessTokenLifespan(),
 		},
-		ScopeStrategy: fosite.HierarchicScopeStrategy,
+		ScopeStrategy: config.GetScopeStrategy(),
 	}
 }
 
@@ -41,6 +44,9 @@ func OAuth2RefreshTokenGrantFactory(config *Config, storage interface{}, strateg
 		RefreshTokenGrantStorage: storage.(oauth2.RefreshTokenGrantStorage),
 		AccessTokenLifespan:      config.GetAccessTokenLifespan(),
 		RefreshTokenLifespan:     config.GetRefreshTokenLifespan(),
+		RefreshTokenMaxLifespan:  config.GetRefreshTokenMaxLifespan(),
+		ScopeStrategy:            config.GetScopeStrategy(),
+		ScopeRegistry:            config.ScopeRegistry,
 	}
 }
 
@@ -51,7 +57,7 @@ func OAuth2AuthorizeImplicitFactory(config *Config, storage interface{}, strateg
 		AccessTokenStrategy: strategy.(oauth2.AccessTokenStrategy),
 		AccessTokenStorage:  storage.(oauth2.AccessTokenStorage),
 		AccessTokenLifespan: config.GetAccessTokenLifespan(),
-		ScopeStrategy:       fosite.HierarchicScopeStrategy,
+		ScopeStrategy:       config.GetScopeStrategy(),
 	}
 }
 
@@ -65,17 +71,22 @@ func OAuth2ResourceOwnerPasswordCredentialsFactory(config *Config, storage inter
 			AccessTokenStorage:  storage.(oauth2.AccessTokenStorage),
 			AccessTokenLifespan: config.GetAccessTokenLifespan(),
 		},
-		RefreshTokenStrategy: strategy.(oauth2.RefreshTokenStrategy),
-		ScopeStrategy:        fosite.HierarchicScopeStrategy,
+		RefreshTokenStrategy:    strategy.(oauth2.RefreshTokenStrategy),
+		ScopeStrategy:           config.GetScopeStrategy(),
+		RefreshTokenLifespan:    config.GetRefreshTokenLifespan(),
+		RefreshTokenMaxLifespan: config.GetRefreshTokenMaxLifespan(),
 	}
 }
 
 // OAuth2TokenRevocationFactory creates an OAuth2 token revocation handler.
 func OAuth2TokenRevocationFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	// Authorization codes are only revocable if the strategy supports them.
+	codeStrategy, _ := strategy.(oauth2.AuthorizeCodeStrategy)
 	return &oauth2.TokenRevocationHandler{
 		TokenRevocationStorage: storage.(oauth2.TokenRevocationStorage),
 		AccessTokenStrategy:    strategy.(oauth2.AccessTokenStrategy),
 		RefreshTokenStrategy:   strategy.(oauth2.RefreshTokenStrategy),
+		AuthorizeCodeStrategy:  codeStrategy,
 	}
 }
 
@@ -85,7 +96,7 @@ func OAuth2TokenIntrospectionFactory(config *Config, storage interface{}, strate
 	return &oauth2.CoreValidator{
 		CoreStrategy:  strategy.(oauth2.CoreStrategy),
 		CoreStorage:   storage.(oauth2.CoreStorage),
-		ScopeStrategy: fosite.HierarchicScopeStrategy,
+		ScopeStrategy: config.GetScopeStrategy(),
 	}
 }
 
@@ -99,6 +110,6 @@ func OAuth2TokenIntrospectionFactory(config *Config, storage interface{}, strate
 func OAuth2StatelessJWTIntrospectionFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
 	return &oauth2.StatelessJWTValidator{
 		JWTAccessTokenStrategy: strategy.(oauth2.JWTAccessTokenStrategy),
-		ScopeStrategy:          fosite.HierarchicScopeStrategy,
+		ScopeStrategy:          config.GetScopeStrategy(),
 	}
 }
@@ -32,10 +32,12 @@ func NewOAuth2JWTStrategy(key *rsa.PrivateKey) *oauth2.RS256JWTStrategy {
 	}
 }
 
-func NewOpenIDConnectStrategy(key *rsa.PrivateKey) *openid.DefaultStrategy {
+func NewOpenIDConnectStrategy(config *Config, key *rsa.PrivateKey) *openid.DefaultStrategy {
 	return &openid.DefaultStrategy{
 		RS256JWTStrategy: &jwt.RS256JWTStrategy{
 			PrivateKey: key,
 		},
+		Expiry:                    config.GetIDTokenLifespan(),
+		SubjectIdentifierStrategy: config.SubjectIdentifierStrategy,
 	}
 }
@@ -1,6 +1,10 @@
 package compose
 
-import "time"
+import (
+	"time"
+
+	"github.com/ory/fosite"
+)
 
 type Config struct {
 	// AccessTokenLifespan sets how long an access token is going to be valid. Defaults to one hour.
@@ -12,11 +16,35 @@ type Config struct {
 	// IDTokenLifespan sets how long an id token is going to be valid. Defaults to one hour.
 	IDTokenLifespan time.Duration
 
-	// RefreshTokenLifespan sets how long a refresh token is going to be valid. Defaults to zero meaning no expiry. Negative values mean the token is permanent.
+	// RefreshTokenLifespan sets how long a refresh token is going to be valid. Refreshing issues a new refresh token, so
+	// this is a sliding (idle) timeout. Defaults to zero meaning no expiry. Negative values mean the token is permanent.
 	RefreshTokenLifespan time.Duration
 
+	// RefreshTokenMaxLifespan sets the absolute lifetime of a refresh token grant, counted from when its first refresh
+	// token was issued. Defaults to zero meaning no absolute limit.
+	RefreshTokenMaxLifespan time.Duration
+
 	// HashCost sets the cost of the password hashing cost. Defaults to 12.
 	HashCost int
+
+	// SubjectIdentifierStrategy transforms the subject of ID tokens and introspection responses, e.g. a
+	// fosite.PairwiseSubjectIdentifierStrategy. Defaults to nil meaning all clients see the same subject.
+	SubjectIdentifierStrategy fosite.SubjectIdentifierStrategy
+
+	// ScopeStrategy matches requested scopes against the scopes clients may request and, for introspection, against
+	// the granted scopes. It is used by all factories. Defaults to fosite.HierarchicScopeStrategy.
+	ScopeStrategy fosite.ScopeStrategy
+
+	// ScopeRegistry is the registry of supported scopes, see fosite.Fosite.ScopeRegistry. Defaults to nil meaning
+	// any scope is accepted.
+	ScopeRegistry fosite.ScopeRegistry
+
+	// RedirectURIMatcher matches the redirect URIs of clients not selecting a matcher. Defaults to
+	// fosite.LoopbackRedirectURIMatcher.
+	RedirectURIMatcher fosite.RedirectURIMatcher
+
+	// RedirectURIMatchers are additional named redirect URI matchers clients may select.
+	RedirectURIMatchers map[string]fosite.RedirectURIMatcher
 }
 
 // GetAuthorizeCodeLifespan returns how long an authorize code should be valid. Defaults to one fifteen minutes.
@@ -40,6 +68,11 @@ func (c *Config) GetRefreshTokenLifespan() time.Duration {
 	return c.RefreshTokenLifespan
 }
 
+// GetRefreshTokenMaxLifespan returns the absolute lifetime of a refresh token grant. Defaults to zero meaning no absolute limit.
+func (c *Config) GetRefreshTokenMaxLifespan() time.Duration {
+	return c.RefreshTokenMaxLifespan
+}
+
 // GetAccessTokenLifespan returns how long a refresh token should be valid. Defaults to one hour.
 func (c *Config) GetAccessTokenLifespan() time.Duration {
 	if c.AccessTokenLifespan == 0 {
@@ -55,3 +88,11 @@ func (c *Config) GetHashCost() int {
 	}
 	return c.HashCost
 }
+
+// GetScopeStrategy returns the scope strategy used by all factories. Defaults to fosite.HierarchicScopeStrategy.
+func (c *Config) GetScopeStrategy() fosite.ScopeStrategy {
+	if c.ScopeStrategy == nil {
+		return fosite.HierarchicScopeStrategy
+	}
+	return c.ScopeStrategy
+}
@@ -0,0 +1,220 @@
+package fosite
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ConsentRecord remembers the scopes and audiences a subject (resource owner) granted to a client.
+type ConsentRecord struct {
+	Subject         string    `json:"subject"`
+	ClientID        string    `json:"client_id"`
+	GrantedScopes   Arguments `json:"granted_scopes"`
+	GrantedAudience Arguments `json:"granted_audience"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	// ExpiresAt is the time the consent expires at. The zero value means it never expires.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// IsExpired returns true if the consent expired before the given time.
+func (c *ConsentRecord) IsExpired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && now.After(c.ExpiresAt)
+}
+
+// ConsentStorage persists consent records. There is at most one record per subject and client.
+type ConsentStorage interface {
+	// SaveConsent creates the record, or replaces the record of the same subject and client.
+	SaveConsent(ctx context.Context, record *ConsentRecord) error
+
+	// GetConsent returns the record of the subject and client, or ErrNotFound. Expired records may be returned.
+	GetConsent(ctx context.Context, subject, clientID string) (*ConsentRecord, error)
+
+	// GetSubjectConsents returns all records of the subject, e.g. to list the clients the subject authorized.
+	GetSubjectConsents(ctx context.Context, subject string) ([]*ConsentRecord, error)
+
+	// RevokeConsent deletes the record of the subject and client, or returns ErrNotFound.
+	RevokeConsent(ctx context.Context, subject, clientID string) error
+}
+
+// ClientWithFirstParty is an optional extension of Client for trusted first-party clients, which are granted all
+// requested scopes and audiences without asking the subject for consent.
+type ClientWithFirstParty interface {
+	// IsFirstParty returns true if the client does not need the subject's consent.
+	IsFirstParty() bool
+
+	Client
+}
+
+// SubjectClientTokenRevoker revokes all tokens issued for a subject to a client, e.g. OAuth2Provider.
+type SubjectClientTokenRevoker interface {
+	RevokeTokensBySubjectAndClient(ctx context.Context, subject, clientID string) error
+}
+
+// ConsentDecision is the result of ConsentStrategy.HandleConsent.
+type ConsentDecision struct {
+	// GrantedScopes and GrantedAudience were granted automatically.
+	GrantedScopes   Arguments
+	GrantedAudience Arguments
+
+	// PendingScopes and PendingAudience need the subject's approval.
+	PendingScopes   Arguments
+	PendingAudience Arguments
+}
+
+// IsConsentRequired returns true if the subject must be asked to approve the pending scopes or audiences.
+func (d *ConsentDecision) IsConsentRequired() bool {
+	return len(d.PendingScopes) > 0 || len(d.PendingAudience) > 0
+}
+
+// ConsentStrategy remembers the scopes and audiences subjects granted to clients, so that the consent screen is only
+// shown for scopes and audiences the subject did not approve yet.
+//
+// A typical authorize endpoint calls HandleConsent after authenticating the subject. If consent is required, the
+// application asks the subject to approve the pending scopes, calls GrantScope for each approved one and then
+// RememberConsent.
+type ConsentStrategy struct {
+	// Storage persists the consent records.
+	Storage ConsentStorage
+
+	// ScopeStrategy matches requested scopes against consented scopes. Defaults to HierarchicScopeStrategy, so
+	// consenting to "photos" covers "photos.read".
+	ScopeStrategy ScopeStrategy
+
+	// ScopeRegistry, if set, grants scopes not requiring consent automatically and never grants sensitive scopes from
+	// remembered consent, see ScopeMetadata.
+	ScopeRegistry ScopeRegistry
+
+	// AudienceMatchingStrategy matches requested audiences against consented audiences. Defaults to
+	// DefaultAudienceMatchingStrategy.
+	AudienceMatchingStrategy AudienceMatchingStrategy
+
+	// Lifespan is how long consent is remembered. Zero means it never expires.
+	Lifespan time.Duration
+
+	// TokenRevoker, if set, revokes the tokens issued for the subject to the client when consent is revoked.
+	TokenRevoker SubjectClientTokenRevoker
+}
+
+// HandleConsent grants the requested scopes and audiences the subject consented to before, or all of them if the
+// client implements ClientWithFirstParty and is a first-party client. Scopes the ScopeRegistry marks with SkipConsent
+// are granted as well unless they are sensitive. The other requested scopes and audiences are returned as pending.
+// The OpenID Connect parameter prompt=consent forces all scopes and audiences to be pending.
+func (s *ConsentStrategy) HandleConsent(ctx context.Context, subject string, ar AuthorizeRequester) (*ConsentDecision, error) {
+	client := ar.GetClient()
+	decision := &ConsentDecision{}
+
+	var record *ConsentRecord
+	var remembered bool
+	if fc, ok := client.(ClientWithFirstParty); ok && fc.IsFirstParty() {
+		record = &ConsentRecord{GrantedScopes: ar.GetRequestedScopes(), GrantedAudience: ar.GetRequestedAudience()}
+	}
+
+	forced := Arguments(removeEmpty(strings.Split(ar.GetRequestForm().Get("prompt"), " "))).Has("consent")
+	if forced {
+		record = nil
+	} else if record == nil {
+		r, err := s.Storage.GetConsent(ctx, subject, client.GetID())
+		if err != nil && errors.Cause(err) != ErrNotFound {
+			return nil, errors.Wrap(ErrServerError, err.Error())
+		} else if err == nil && !r.IsExpired(time.Now().UTC()) {
+			record = r
+			remembered = true
+		}
+	}
+
+	for _, scope := range ar.GetRequestedScopes() {
+		metadata, registered := s.ScopeRegistry.Get(scope)
+		if registered && metadata.SkipConsent && !metadata.Sensitive && !forced {
+			ar.GrantScope(scope)
+			decision.GrantedScopes = append(decision.GrantedScopes, scope)
+		} else if record != nil && s.scopeStrategy()(record.GrantedScopes, scope) && !(remembered && metadata.Sensitive) {
+			ar.GrantScope(scope)
+			decision.GrantedScopes = append(decision.GrantedScopes, scope)
+		} else {
+			decision.PendingScopes = append(decision.PendingScopes, scope)
+		}
+	}
+	for _, audience := range ar.GetRequestedAudience() {
+		if record != nil && s.audienceMatchingStrategy()(record.GrantedAudience, audience) {
+			ar.GrantAudience(audience)
+			decision.GrantedAudience = append(decision.GrantedAudience, audience)
+		} else {
+			decision.PendingAudience = append(decision.PendingAudience, audience)
+		}
+	}
+
+	return decision, nil
+}
+
+// RememberConsent saves the scopes and audiences granted in the request, in addition to those the subject consented
+// to before. The expiry is reset to Lifespan.
+func (s *ConsentStrategy) RememberConsent(ctx context.Context, subject string, ar AuthorizeRequester) error {
+	now := time.Now().UTC()
+	record := &ConsentRecord{
+		Subject:   subject,
+		ClientID:  ar.GetClient().GetID(),
+		CreatedAt: now,
+	}
+	if s.Lifespan > 0 {
+		record.ExpiresAt = now.Add(s.Lifespan)
+	}
+
+	previous, err := s.Storage.GetConsent(ctx, subject, record.ClientID)
+	if err != nil && errors.Cause(err) != ErrNotFound {
+		return errors.Wrap(ErrServerError, err.Error())
+	} else if err == nil && !previous.IsExpired(now) {
+		record.GrantedScopes = append(record.GrantedScopes, previous.GrantedScopes...)
+		record.GrantedAudience = append(record.GrantedAudience, previous.GrantedAudience...)
+	}
+
+	for _, scope := range ar.GetGrantedScopes() {
+		if !record.GrantedScopes.Has(scope) {
+			record.GrantedScopes = append(record.GrantedScopes, scope)
+		}
+	}
+	for _, audience := range ar.GetGrantedAudience() {
+		if !record.GrantedAudience.Has(audience) {
+			record.GrantedAudience = append(record.GrantedAudience, audience)
+		}
+	}
+
+	if err := s.Storage.SaveConsent(ctx, record); err != nil {
+		return errors.Wrap(ErrServerError, err.Error())
+	}
+	return nil
+}
+
+// RevokeConsent forgets the consent of the subject to the client and revokes the tokens issued for it if
+// TokenRevoker is set. Revoking consent that does not exist is not an error.
+func (s *ConsentStrategy) RevokeConsent(ctx context.Context, subject, clientID string) error {
+	if subject == "" || clientID == "" {
+		return errors.Wrap(ErrInvalidRequest, "Subject and client ID must not be empty")
+	}
+
+	if err := s.Storage.RevokeConsent(ctx, subject, clientID); err != nil && errors.Cause(err) != ErrNotFound {
+		return errors.Wrap(ErrServerError, err.Error())
+	}
+
+	if s.TokenRevoker != nil {
+		return s.TokenRevoker.RevokeTokensBySubjectAndClient(ctx, subject, clientID)
+	}
+	return nil
+}
+
+func (s *ConsentStrategy) scopeStrategy() ScopeStrategy {
+	if s.ScopeStrategy == nil {
+		return HierarchicScopeStrategy
+	}
+	return s.ScopeStrategy
+}
+
+func (s *ConsentStrategy) audienceMatchingStrategy() AudienceMatchingStrategy {
+	if s.AudienceMatchingStrategy == nil {
+		return DefaultAudienceMatchingStrategy
+	}
+	return s.AudienceMatchingStrategy
+}
@@ -0,0 +1,122 @@
+package fosite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/ory/fosite"
+	"github.com/ory/fosite/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type subjectClientRevoker [][2]string
+
+func (r *subjectClientRevoker) RevokeTokensBySubjectAndClient(_ context.Context, subject, clientID string) error {
+	*r = append(*r, [2]string{subject, clientID})
+	return nil
+}
+
+func newConsentRequest(client Client, scopes []string, audience []string) *AuthorizeRequest {
+	ar := NewAuthorizeRequest()
+	ar.Client = client
+	ar.SetRequestedScopes(scopes)
+	ar.SetRequestedAudience(audience)
+	return ar
+}
+
+func TestConsentStrategy(t *testing.T) {
+	store := storage.NewMemoryStore()
+	revoker := &subjectClientRevoker{}
+	s := &ConsentStrategy{Storage: store, Lifespan: time.Hour, TokenRevoker: revoker}
+	client := &DefaultClient{ID: "foo"}
+
+	ar := newConsentRequest(client, []string{"openid", "photos"}, []string{"https://api.example.com"})
+	decision, err := s.HandleConsent(nil, "peter", ar)
+	require.Nil(t, err)
+	assert.True(t, decision.IsConsentRequired())
+	assert.Equal(t, Arguments{"openid", "photos"}, decision.PendingScopes)
+	assert.Equal(t, Arguments{"https://api.example.com"}, decision.PendingAudience)
+	assert.Empty(t, ar.GetGrantedScopes())
+
+	// The subject approves everything but the audience.
+	ar.GrantScope("openid")
+	ar.GrantScope("photos")
+	require.Nil(t, s.RememberConsent(nil, "peter", ar))
+
+	record, err := store.GetConsent(nil, "peter", "foo")
+	require.Nil(t, err)
+	assert.Equal(t, Arguments{"openid", "photos"}, record.GrantedScopes)
+	assert.True(t, record.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+
+	ar = newConsentRequest(client, []string{"openid", "photos.read", "contacts"}, []string{"https://api.example.com"})
+	decision, err = s.HandleConsent(nil, "peter", ar)
+	require.Nil(t, err)
+	assert.True(t, decision.IsConsentRequired())
+	assert.Equal(t, Arguments{"openid", "photos.read"}, decision.GrantedScopes)
+	assert.Equal(t, Arguments{"contacts"}, decision.PendingScopes)
+	assert.Equal(t, Arguments{"https://api.example.com"}, decision.PendingAudience)
+	assert.Equal(t, Arguments{"openid", "photos.read"}, ar.GetGrantedScopes())
+
+	// Approving the rest is remembered in addition to the previous consent.
+	ar.GrantScope("contacts")
+	ar.GrantAudience("https://api.example.com")
+	require.Nil(t, s.RememberConsent(nil, "peter", ar))
+
+	ar = newConsentRequest(client, []string{"openid", "photos", "contacts"}, []string{"https://api.example.com/photos"})
+	decision, err = s.HandleConsent(nil, "peter", ar)
+	require.Nil(t, err)
+	assert.False(t, decision.IsConsentRequired())
+	assert.Equal(t, Arguments{"openid", "photos", "contacts"}, ar.GetGrantedScopes())
+	assert.Equal(t, Arguments{"https://api.example.com/photos"}, ar.GetGrantedAudience())
+
+	// prompt=consent forces the consent screen.
+	ar = newConsentRequest(client, []string{"openid"}, nil)
+	ar.Form.Set("prompt", "login consent")
+	decision, err = s.HandleConsent(nil, "peter", ar)
+	require.Nil(t, err)
+	assert.Equal(t, Arguments{"openid"}, decision.PendingScopes)
+
+	// Other subjects did not consent.
+	decision, err = s.HandleConsent(nil, "alice", newConsentRequest(client, []string{"openid"}, nil))
+	require.Nil(t, err)
+	assert.True(t, decision.IsConsentRequired())
+
+	records, err := store.GetSubjectConsents(nil, "peter")
+	require.Nil(t, err)
+	assert.Len(t, records, 1)
+
+	require.Nil(t, s.RevokeConsent(nil, "peter", "foo"))
+	assert.Equal(t, subjectClientRevoker{{"peter", "foo"}}, *revoker)
+	decision, err = s.HandleConsent(nil, "peter", newConsentRequest(client, []string{"openid"}, nil))
+	require.Nil(t, err)
+	assert.True(t, decision.IsConsentRequired())
+	require.Nil(t, s.RevokeConsent(nil, "peter", "foo"))
+}
+
+func TestConsentStrategyExpiredConsent(t *testing.T) {
+	store := storage.NewMemoryStore()
+	s := &ConsentStrategy{Storage: store}
+	require.Nil(t, store.SaveConsent(nil, &ConsentRecord{
+		Subject:       "peter",
+		ClientID:      "foo",
+		GrantedScopes: Arguments{"openid"},
+		ExpiresAt:     time.Now().Add(-time.Minute),
+	}))
+
+	decision, err := s.HandleConsent(nil, "peter", newConsentRequest(&DefaultClient{ID: "foo"}, []string{"openid"}, nil))
+	require.Nil(t, err)
+	assert.Equal(t, Arguments{"openid"}, decision.PendingScopes)
+}
+
+func TestConsentStrategyFirstPartyClient(t *testing.T) {
+	s := &ConsentStrategy{Storage: storage.NewMemoryStore()}
+	ar := newConsentRequest(&DefaultClient{ID: "foo", FirstParty: true}, []string{"openid", "photos"}, []string{"https://api.example.com"})
+
+	decision, err := s.HandleConsent(nil, "peter", ar)
+	require.Nil(t, err)
+	assert.False(t, decision.IsConsentRequired())
+	assert.Equal(t, Arguments{"openid", "photos"}, ar.GetGrantedScopes())
+	assert.Equal(t, Arguments{"https://api.example.com"}, ar.GetGrantedAudience())
+}
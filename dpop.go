@@ -0,0 +1,362 @@
+package fosite
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+// DPoPStorage records the identifiers of DPoP proofs that were already used, as required by
+// https://tools.ietf.org/html/rfc9449#section-11.1
+type DPoPStorage interface {
+	// SetDPoPProofUsed records that the proof with the given jti was used. The record is only required until exp.
+	// It must return an error if a proof with the same jti was already recorded and did not expire yet.
+	SetDPoPProofUsed(ctx context.Context, jti string, exp time.Time) error
+}
+
+// DPoPNonceStrategy issues and validates server-provided nonces as defined in
+// https://tools.ietf.org/html/rfc9449#section-8
+type DPoPNonceStrategy interface {
+	// GenerateDPoPNonce returns a fresh nonce the client must include in its next DPoP proof.
+	GenerateDPoPNonce(ctx context.Context) (string, error)
+
+	// ValidateDPoPNonce returns an error if the nonce was not issued by this server or is no longer valid.
+	ValidateDPoPNonce(ctx context.Context, nonce string) error
+}
+
+// DPoPBoundRequester is implemented by requests whose tokens can be bound to the key of a DPoP proof. Request
+// implements it.
+type DPoPBoundRequester interface {
+	// GetDPoPJWKThumbprint returns the JWK SHA-256 thumbprint of the key the tokens are bound to, or an empty
+	// string for bearer tokens.
+	GetDPoPJWKThumbprint() string
+
+	// SetDPoPJWKThumbprint binds the tokens to the key with the given JWK SHA-256 thumbprint.
+	SetDPoPJWKThumbprint(thumbprint string)
+}
+
+// GetDPoPJWKThumbprint returns the thumbprint of the key the request's tokens are bound to, or an empty string if the
+// tokens are bearer tokens or the request does not implement DPoPBoundRequester.
+func GetDPoPJWKThumbprint(r Requester) string {
+	if dr, ok := r.(DPoPBoundRequester); ok {
+		return dr.GetDPoPJWKThumbprint()
+	}
+	return ""
+}
+
+// DPoPNonceError is returned when a DPoP proof does not contain a valid server-provided nonce. It carries a fresh
+// nonce which must be sent to the client in the DPoP-Nonce header. Its cause is ErrUseDPoPNonce.
+type DPoPNonceError struct {
+	Nonce string
+}
+
+func (e *DPoPNonceError) Error() string {
+	return ErrUseDPoPNonce.Error()
+}
+
+func (e *DPoPNonceError) Cause() error {
+	return ErrUseDPoPNonce
+}
+
+// DPoPNonceFromError returns the fresh nonce carried by err, or an empty string if err is not caused by a
+// DPoPNonceError.
+func DPoPNonceFromError(err error) string {
+	for err != nil {
+		if e, ok := err.(*DPoPNonceError); ok {
+			return e.Nonce
+		}
+
+		c, ok := err.(interface {
+			Cause() error
+		})
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+	return ""
+}
+
+// DPoPProof is a validated DPoP proof.
+type DPoPProof struct {
+	JTI             string
+	Method          string
+	URI             string
+	IssuedAt        time.Time
+	Nonce           string
+	AccessTokenHash string
+
+	// JWKThumbprint is the JWK SHA-256 thumbprint (RFC 7638) of the proof's public key.
+	JWKThumbprint string
+}
+
+// DefaultDPoPProofLifespan is the default time after a DPoP proof's iat during which the proof is accepted.
+const DefaultDPoPProofLifespan = time.Minute * 5
+
+// DPoPStrategy validates DPoP proofs as defined in https://tools.ietf.org/html/rfc9449
+type DPoPStrategy struct {
+	// Storage is used to reject proofs that were already used. It is required.
+	Storage DPoPStorage
+
+	// NonceStrategy, if set, requires proofs to contain a nonce issued by it.
+	NonceStrategy DPoPNonceStrategy
+
+	// ProofLifespan defines how long after its iat a proof is accepted. Defaults to DefaultDPoPProofLifespan.
+	ProofLifespan time.Duration
+
+	// ClockSkew defines how far in the future a proof's iat may be. Defaults to zero.
+	ClockSkew time.Duration
+
+	// SigningMethods are the accepted proof signature algorithms. Defaults to the RSA, RSA-PSS and ECDSA
+	// algorithms.
+	SigningMethods []string
+
+	// RequestURL returns the URL a request was sent to, which is compared to the proof's htu claim. Defaults to
+	// an URL made of the request's scheme, host and path, which might be incorrect if fosite runs behind a proxy.
+	RequestURL func(r *http.Request) string
+}
+
+var defaultDPoPSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// ValidateRequestProof validates the DPoP proof sent with the request. The access token is empty at the token
+// endpoint, and otherwise the token the proof must be bound to. It returns nil if the request does not contain a
+// proof.
+func (s *DPoPStrategy) ValidateRequestProof(ctx context.Context, r *http.Request, accessToken string) (*DPoPProof, error) {
+	proofs := r.Header[http.CanonicalHeaderKey("DPoP")]
+	if len(proofs) == 0 {
+		return nil, nil
+	} else if len(proofs) > 1 {
+		return nil, errors.Wrap(ErrInvalidDPoPProof, "The request contains more than one DPoP header")
+	}
+
+	requestURL := s.requestURL(r)
+	return s.ValidateProof(ctx, proofs[0], r.Method, requestURL, accessToken)
+}
+
+// ValidateProof validates a DPoP proof as defined in https://tools.ietf.org/html/rfc9449#section-4.3 for a request
+// with the given method and URL. If the access token is not empty, the proof must contain its hash.
+func (s *DPoPStrategy) ValidateProof(ctx context.Context, proof string, method string, uri string, accessToken string) (*DPoPProof, error) {
+	var thumbprint string
+	parser := &jwtx.Parser{ValidMethods: s.signingMethods(), SkipClaimsValidation: true}
+	token, err := parser.Parse(proof, func(t *jwtx.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != "dpop+jwt" {
+			return nil, errors.Errorf("Header typ must be dpop+jwt but got %v", t.Header["typ"])
+		}
+
+		jwk, ok := t.Header["jwk"].(map[string]interface{})
+		if !ok {
+			return nil, errors.New("Header jwk is missing or not a JSON object")
+		}
+
+		key, tp, err := parseDPoPJWK(jwk)
+		if err != nil {
+			return nil, err
+		}
+		thumbprint = tp
+		return key, nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidDPoPProof, err.Error())
+	}
+
+	claims, _ := token.Claims.(jwtx.MapClaims)
+	p := &DPoPProof{JWKThumbprint: thumbprint}
+	p.JTI, _ = claims["jti"].(string)
+	p.Method, _ = claims["htm"].(string)
+	p.URI, _ = claims["htu"].(string)
+	p.Nonce, _ = claims["nonce"].(string)
+	p.AccessTokenHash, _ = claims["ath"].(string)
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidDPoPProof, "Claim iat is missing")
+	}
+	p.IssuedAt = time.Unix(int64(iat), 0)
+
+	if p.JTI == "" {
+		return nil, errors.Wrap(ErrInvalidDPoPProof, "Claim jti is missing")
+	} else if p.Method != method {
+		return nil, errors.Wrapf(ErrInvalidDPoPProof, "Claim htm must be %s but got %s", method, p.Method)
+	} else if !dpopURIsMatch(p.URI, uri) {
+		return nil, errors.Wrapf(ErrInvalidDPoPProof, "Claim htu must be %s but got %s", uri, p.URI)
+	}
+
+	now := time.Now()
+	exp := p.IssuedAt.Add(s.proofLifespan())
+	if p.IssuedAt.After(now.Add(s.ClockSkew)) {
+		return nil, errors.Wrap(ErrInvalidDPoPProof, "Claim iat is in the future")
+	} else if exp.Before(now) {
+		return nil, errors.Wrap(ErrInvalidDPoPProof, "The proof expired")
+	}
+
+	if accessToken != "" && p.AccessTokenHash != dpopAccessTokenHash(accessToken) {
+		return nil, errors.Wrap(ErrInvalidDPoPProof, "Claim ath does not match the access token")
+	}
+
+	if s.NonceStrategy != nil {
+		if p.Nonce == "" || s.NonceStrategy.ValidateDPoPNonce(ctx, p.Nonce) != nil {
+			nonce, err := s.NonceStrategy.GenerateDPoPNonce(ctx)
+			if err != nil {
+				return nil, errors.Wrap(ErrServerError, err.Error())
+			}
+			return nil, errors.WithStack(&DPoPNonceError{Nonce: nonce})
+		}
+	}
+
+	if err := s.Storage.SetDPoPProofUsed(ctx, p.JTI, exp.Add(s.ClockSkew)); err != nil {
+		return nil, errors.Wrap(ErrInvalidDPoPProof, "The proof was already used")
+	}
+
+	return p, nil
+}
+
+func (s *DPoPStrategy) proofLifespan() time.Duration {
+	if s.ProofLifespan == 0 {
+		return DefaultDPoPProofLifespan
+	}
+	return s.ProofLifespan
+}
+
+func (s *DPoPStrategy) signingMethods() []string {
+	if len(s.SigningMethods) == 0 {
+		return defaultDPoPSigningMethods
+	}
+	return s.SigningMethods
+}
+
+func (s *DPoPStrategy) requestURL(r *http.Request) string {
+	if s.RequestURL != nil {
+		return s.RequestURL(r)
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.Path
+}
+
+// ValidateDPoPBoundToken validates that a request to a resource server proves possession of the key a DPoP-bound
+// access token is bound to, as defined in https://tools.ietf.org/html/rfc9449#section-7.1. The requester is the
+// result of introspecting the access token, e.g. using IntrospectToken. Requests using bearer tokens pass.
+func (f *Fosite) ValidateDPoPBoundToken(ctx context.Context, r *http.Request, accessToken string, requester Requester) error {
+	thumbprint := GetDPoPJWKThumbprint(requester)
+	if thumbprint == "" {
+		return nil
+	} else if f.DPoPStrategy == nil {
+		return errors.Wrap(ErrMisconfiguration, "The access token is bound to a DPoP key but DPoP is not configured")
+	}
+
+	if scheme := strings.SplitN(r.Header.Get("Authorization"), " ", 2)[0]; !strings.EqualFold(scheme, "dpop") {
+		return errors.Wrap(ErrInvalidDPoPProof, "The access token is bound to a DPoP key and must use the DPoP authorization scheme")
+	}
+
+	proof, err := f.DPoPStrategy.ValidateRequestProof(ctx, r, accessToken)
+	if err != nil {
+		return err
+	} else if proof == nil {
+		return errors.Wrap(ErrInvalidDPoPProof, "The access token is bound to a DPoP key but the request contains no DPoP proof")
+	} else if proof.JWKThumbprint != thumbprint {
+		return errors.Wrap(ErrInvalidDPoPProof, "The DPoP proof was not signed with the key the access token is bound to")
+	}
+	return nil
+}
+
+func dpopURIsMatch(claim, uri string) bool {
+	c, err := url.Parse(claim)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(c.Scheme, u.Scheme) && strings.EqualFold(c.Host, u.Host) && c.Path == u.Path
+}
+
+func dpopAccessTokenHash(accessToken string) string {
+	hash := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// parseDPoPJWK parses an RSA or EC public key in JWK format and computes its JWK SHA-256 thumbprint as defined in
+// https://tools.ietf.org/html/rfc7638
+func parseDPoPJWK(jwk map[string]interface{}) (interface{}, string, error) {
+	member := func(name string) (string, []byte, error) {
+		v, _ := jwk[name].(string)
+		b, err := base64.RawURLEncoding.DecodeString(v)
+		if err != nil || len(b) == 0 {
+			return "", nil, errors.Errorf("JWK member %s is missing or malformed", name)
+		}
+		return v, b, nil
+	}
+
+	if _, ok := jwk["d"]; ok {
+		return nil, "", errors.New("JWK must not contain a private key")
+	}
+
+	var key interface{}
+	var canonical string
+	switch kty, _ := jwk["kty"].(string); kty {
+	case "RSA":
+		n, nb, err := member("n")
+		if err != nil {
+			return nil, "", err
+		}
+		e, eb, err := member("e")
+		if err != nil {
+			return nil, "", err
+		} else if len(eb) > 4 {
+			return nil, "", errors.New("JWK member e is too large")
+		}
+
+		key = &rsa.PublicKey{N: new(big.Int).SetBytes(nb), E: int(new(big.Int).SetBytes(eb).Int64())}
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, e, n)
+	case "EC":
+		var curve elliptic.Curve
+		crv, _ := jwk["crv"].(string)
+		switch crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, "", errors.Errorf("JWK curve %s is not supported", crv)
+		}
+
+		x, xb, err := member("x")
+		if err != nil {
+			return nil, "", err
+		}
+		y, yb, err := member("y")
+		if err != nil {
+			return nil, "", err
+		}
+
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(xb), Y: new(big.Int).SetBytes(yb)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, "", errors.New("JWK point is not on the curve")
+		}
+
+		key = pub
+		canonical = fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`, crv, x, y)
+	default:
+		return nil, "", errors.Errorf("JWK key type %s is not supported", kty)
+	}
+
+	hash := sha256.Sum256([]byte(canonical))
+	return key, base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
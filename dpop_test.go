@@ -0,0 +1,280 @@
+package fosite
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dpopTestStorage map[string]time.Time
+
+func (s dpopTestStorage) SetDPoPProofUsed(_ context.Context, jti string, exp time.Time) error {
+	if _, ok := s[jti]; ok {
+		return errors.New("already used")
+	}
+	s[jti] = exp
+	return nil
+}
+
+type dpopTestNonceStrategy string
+
+func (s dpopTestNonceStrategy) GenerateDPoPNonce(_ context.Context) (string, error) {
+	return string(s), nil
+}
+
+func (s dpopTestNonceStrategy) ValidateDPoPNonce(_ context.Context, nonce string) error {
+	if nonce != string(s) {
+		return errors.New("invalid nonce")
+	}
+	return nil
+}
+
+func newDPoPTestKey(t *testing.T) (*ecdsa.PrivateKey, map[string]interface{}) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	return key, map[string]interface{}{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+}
+
+func newDPoPTestProof(t *testing.T, key *ecdsa.PrivateKey, header map[string]interface{}, claims jwtx.MapClaims) string {
+	token := jwtx.NewWithClaims(jwtx.SigningMethodES256, claims)
+	for k, v := range header {
+		token.Header[k] = v
+	}
+	proof, err := token.SignedString(key)
+	require.Nil(t, err)
+	return proof
+}
+
+func TestParseDPoPJWK(t *testing.T) {
+	// https://tools.ietf.org/html/rfc7638#section-3.1
+	_, thumbprint, err := parseDPoPJWK(map[string]interface{}{
+		"kty": "RSA",
+		"n":   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		"e":   "AQAB",
+		"alg": "RS256",
+		"kid": "2011-04-29",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+
+	for k, jwk := range []map[string]interface{}{
+		{"kty": "oct", "k": "c2VjcmV0"},
+		{"kty": "RSA", "n": "0vx7", "e": "AQAB", "d": "AQAB"},
+		{"kty": "RSA", "e": "AQAB"},
+		{"kty": "EC", "crv": "P-256", "x": "AQAB", "y": "AQAB"},
+		{"kty": "EC", "crv": "secp256k1", "x": "AQAB", "y": "AQAB"},
+	} {
+		_, _, err := parseDPoPJWK(jwk)
+		assert.NotNil(t, err, "(%d)", k)
+	}
+}
+
+func TestDPoPStrategy_ValidateProof(t *testing.T) {
+	key, jwk := newDPoPTestKey(t)
+	_, otherJWK := newDPoPTestKey(t)
+	_, expectedThumbprint, err := parseDPoPJWK(jwk)
+	require.Nil(t, err)
+
+	header := map[string]interface{}{"typ": "dpop+jwt", "jwk": jwk}
+	claims := func(modify func(c jwtx.MapClaims)) jwtx.MapClaims {
+		c := jwtx.MapClaims{
+			"jti": uuid.New(),
+			"htm": "POST",
+			"htu": "https://server.example.com/token",
+			"iat": time.Now().Unix(),
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+
+	replayed := newDPoPTestProof(t, key, header, claims(nil))
+	for k, c := range []struct {
+		description string
+		strategy    *DPoPStrategy
+		proof       string
+		accessToken string
+		expectErr   error
+	}{
+		{
+			description: "should pass",
+			proof:       newDPoPTestProof(t, key, header, claims(nil)),
+		},
+		{
+			description: "should pass with a different htu query and case",
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				c["htu"] = "https://Server.Example.com/token?foo=bar"
+			})),
+		},
+		{
+			description: "should pass with the access token hash",
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				c["ath"] = dpopAccessTokenHash("access-token")
+			})),
+			accessToken: "access-token",
+		},
+		{
+			description: "should fail because the proof was already used",
+			proof:       replayed,
+			expectErr:   ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because typ is wrong",
+			proof:       newDPoPTestProof(t, key, map[string]interface{}{"typ": "JWT", "jwk": jwk}, claims(nil)),
+			expectErr:   ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because jwk is missing",
+			proof:       newDPoPTestProof(t, key, map[string]interface{}{"typ": "dpop+jwt"}, claims(nil)),
+			expectErr:   ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because the proof was signed with another key",
+			proof:       newDPoPTestProof(t, key, map[string]interface{}{"typ": "dpop+jwt", "jwk": otherJWK}, claims(nil)),
+			expectErr:   ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because the algorithm is not allowed",
+			strategy:    &DPoPStrategy{SigningMethods: []string{"RS256"}},
+			proof:       newDPoPTestProof(t, key, header, claims(nil)),
+			expectErr:   ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because htm is wrong",
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				c["htm"] = "GET"
+			})),
+			expectErr: ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because htu is wrong",
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				c["htu"] = "https://server.example.com/authorize"
+			})),
+			expectErr: ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because jti is missing",
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				delete(c, "jti")
+			})),
+			expectErr: ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because the proof expired",
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				c["iat"] = time.Now().Add(-time.Hour).Unix()
+			})),
+			expectErr: ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because iat is in the future",
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				c["iat"] = time.Now().Add(time.Minute).Unix()
+			})),
+			expectErr: ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because the access token hash does not match",
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				c["ath"] = dpopAccessTokenHash("other-token")
+			})),
+			accessToken: "access-token",
+			expectErr:   ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because the nonce is missing",
+			strategy:    &DPoPStrategy{NonceStrategy: dpopTestNonceStrategy("nonce")},
+			proof:       newDPoPTestProof(t, key, header, claims(nil)),
+			expectErr:   ErrUseDPoPNonce,
+		},
+		{
+			description: "should pass with a valid nonce",
+			strategy:    &DPoPStrategy{NonceStrategy: dpopTestNonceStrategy("nonce")},
+			proof: newDPoPTestProof(t, key, header, claims(func(c jwtx.MapClaims) {
+				c["nonce"] = "nonce"
+			})),
+		},
+	} {
+		s := c.strategy
+		if s == nil {
+			s = &DPoPStrategy{}
+		}
+		s.Storage = dpopTestStorage{}
+
+		if c.proof == replayed {
+			_, err := s.ValidateProof(nil, c.proof, "POST", "https://server.example.com/token", c.accessToken)
+			require.Nil(t, err)
+		}
+
+		proof, err := s.ValidateProof(nil, c.proof, "POST", "https://server.example.com/token", c.accessToken)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expectErr == nil {
+			assert.Equal(t, expectedThumbprint, proof.JWKThumbprint, "(%d) %s", k, c.description)
+		} else if c.expectErr == ErrUseDPoPNonce {
+			assert.Equal(t, "nonce", DPoPNonceFromError(err), "(%d) %s", k, c.description)
+		}
+	}
+}
+
+func TestValidateDPoPBoundToken(t *testing.T) {
+	key, jwk := newDPoPTestKey(t)
+	_, thumbprint, err := parseDPoPJWK(jwk)
+	require.Nil(t, err)
+
+	f := &Fosite{DPoPStrategy: &DPoPStrategy{Storage: dpopTestStorage{}}}
+	newRequest := func(scheme string, ath string) *http.Request {
+		r := &http.Request{
+			Method: "GET",
+			Host:   "api.example.com",
+			URL:    &url.URL{Path: "/resource"},
+			Header: http.Header{"Authorization": {scheme + " access-token"}},
+		}
+		r.Header.Set("DPoP", newDPoPTestProof(t, key, map[string]interface{}{"typ": "dpop+jwt", "jwk": jwk}, jwtx.MapClaims{
+			"jti": uuid.New(),
+			"htm": "GET",
+			"htu": "http://api.example.com/resource",
+			"iat": time.Now().Unix(),
+			"ath": ath,
+		}))
+		return r
+	}
+
+	bound := NewRequest()
+	bound.SetDPoPJWKThumbprint(thumbprint)
+	otherKey := NewRequest()
+	otherKey.SetDPoPJWKThumbprint("other-thumbprint")
+
+	for k, c := range []struct {
+		r         *http.Request
+		requester Requester
+		expectErr error
+	}{
+		{r: newRequest("DPoP", dpopAccessTokenHash("access-token")), requester: bound},
+		{r: newRequest("Bearer", ""), requester: NewRequest()},
+		{r: newRequest("Bearer", dpopAccessTokenHash("access-token")), requester: bound, expectErr: ErrInvalidDPoPProof},
+		{r: newRequest("DPoP", dpopAccessTokenHash("other-token")), requester: bound, expectErr: ErrInvalidDPoPProof},
+		{r: newRequest("DPoP", dpopAccessTokenHash("access-token")), requester: otherKey, expectErr: ErrInvalidDPoPProof},
+	} {
+		err := f.ValidateDPoPBoundToken(nil, c.r, AccessTokenFromRequest(c.r), c.requester)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s", k, err, c.expectErr)
+	}
+}
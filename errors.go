@@ -7,54 +7,72 @@ import (
 )
 
 var (
-	ErrRequestUnauthorized     = errors.New("The request could not be authorized")
-	ErrRequestForbidden        = errors.New("The request is not allowed")
-	ErrInvalidRequest          = errors.New("The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed")
-	ErrUnauthorizedClient      = errors.New("The client is not authorized to request a token using this method")
-	ErrAccessDenied            = errors.New("The resource owner or authorization server denied the request")
-	ErrUnsupportedResponseType = errors.New("The authorization server does not support obtaining a token using this method")
-	ErrInvalidScope            = errors.New("The requested scope is invalid, unknown, or malformed")
-	ErrServerError             = errors.New("The authorization server encountered an unexpected condition that prevented it from fulfilling the request")
-	ErrTemporarilyUnavailable  = errors.New("The authorization server is currently unable to handle the request due to a temporary overloading or maintenance of the server")
-	ErrUnsupportedGrantType    = errors.New("The authorization grant type is not supported by the authorization server")
-	ErrInvalidGrant            = errors.New("The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client")
-	ErrInvalidClient           = errors.New("Client authentication failed (e.g., unknown client, no client authentication included, or unsupported authentication method)")
-	ErrInvalidState            = errors.Errorf("The state is missing or has less than %d characters and is therefore considered too weak", MinParameterEntropy)
-	ErrInsufficientEntropy     = errors.Errorf("The request used a security parameter (e.g., anti-replay, anti-csrf) with insufficient entropy (minimum of %d characters)", MinParameterEntropy)
-	ErrMisconfiguration        = errors.New("The request failed because of an internal error that is probably caused by misconfiguration")
-	ErrNotFound                = errors.New("Could not find the requested resource(s)")
-	ErrInvalidTokenFormat      = errors.New("Invalid token format")
-	ErrTokenSignatureMismatch  = errors.New("Token signature mismatch")
-	ErrTokenExpired            = errors.New("Token expired")
-	ErrScopeNotGranted         = errors.New("The token was not granted the requested scope")
-	ErrTokenClaim              = errors.New("The token failed validation due to a claim mismatch")
-	ErrInactiveToken           = errors.New("Token is inactive because it is malformed, expired or otherwise invalid")
+	ErrRequestUnauthorized         = errors.New("The request could not be authorized")
+	ErrRequestForbidden            = errors.New("The request is not allowed")
+	ErrInvalidRequest              = errors.New("The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed")
+	ErrUnauthorizedClient          = errors.New("The client is not authorized to request a token using this method")
+	ErrAccessDenied                = errors.New("The resource owner or authorization server denied the request")
+	ErrUnsupportedResponseType     = errors.New("The authorization server does not support obtaining a token using this method")
+	ErrInvalidScope                = errors.New("The requested scope is invalid, unknown, or malformed")
+	ErrServerError                 = errors.New("The authorization server encountered an unexpected condition that prevented it from fulfilling the request")
+	ErrTemporarilyUnavailable      = errors.New("The authorization server is currently unable to handle the request due to a temporary overloading or maintenance of the server")
+	ErrUnsupportedGrantType        = errors.New("The authorization grant type is not supported by the authorization server")
+	ErrInvalidGrant                = errors.New("The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client")
+	ErrInvalidClient               = errors.New("Client authentication failed (e.g., unknown client, no client authentication included, or unsupported authentication method)")
+	ErrInvalidState                = errors.Errorf("The state is missing or has less than %d characters and is therefore considered too weak", MinParameterEntropy)
+	ErrInsufficientEntropy         = errors.Errorf("The request used a security parameter (e.g., anti-replay, anti-csrf) with insufficient entropy (minimum of %d characters)", MinParameterEntropy)
+	ErrMisconfiguration            = errors.New("The request failed because of an internal error that is probably caused by misconfiguration")
+	ErrNotFound                    = errors.New("Could not find the requested resource(s)")
+	ErrInvalidTokenFormat          = errors.New("Invalid token format")
+	ErrTokenSignatureMismatch      = errors.New("Token signature mismatch")
+	ErrTokenExpired                = errors.New("Token expired")
+	ErrScopeNotGranted             = errors.New("The token was not granted the requested scope")
+	ErrTokenClaim                  = errors.New("The token failed validation due to a claim mismatch")
+	ErrInactiveToken               = errors.New("Token is inactive because it is malformed, expired or otherwise invalid")
+	ErrInvalidTarget               = errors.New("The requested resource is invalid, missing, unknown, or malformed")
+	ErrInvalidAuthorizationDetails = errors.New("The requested authorization details are invalid, unknown, or malformed")
+	ErrInvalidDPoPProof            = errors.New("The DPoP proof is invalid")
+	ErrUseDPoPNonce                = errors.New("The authorization server requires a nonce in the DPoP proof")
+	ErrUnsupportedTokenType        = errors.New("The authorization server does not support the revocation of the presented token type")
+	ErrInvalidRedirectURI          = errors.New("The value of one or more redirection URIs is invalid")
+	ErrInvalidClientMetadata       = errors.New("The value of one of the client metadata fields is invalid and the server has rejected this request")
+	ErrInvalidSoftwareStatement    = errors.New("The software statement presented is invalid")
+	ErrUnapprovedSoftwareStatement = errors.New("The software statement presented is not approved for use by this authorization server")
 )
 
 const (
-	errRequestUnauthorized         = "request_unauthorized"
-	errRequestForbidden            = "request_forbidden"
-	errInvalidRequestName          = "invalid_request"
-	errUnauthorizedClientName      = "unauthorized_client"
-	errAccessDeniedName            = "access_denied"
-	errUnsupportedResponseTypeName = "unsupported_response_type"
-	errInvalidScopeName            = "invalid_scope"
-	errServerErrorName             = "server_error"
-	errTemporarilyUnavailableName  = "temporarily_unavailable"
-	errUnsupportedGrantTypeName    = "unsupported_grant_type"
-	errInvalidGrantName            = "invalid_grant"
-	errInvalidClientName           = "invalid_client"
-	UnknownErrorName               = "unknown_error"
-	errNotFound                    = "not_found"
-	errInvalidState                = "invalid_state"
-	errMisconfiguration            = "misconfiguration"
-	errInsufficientEntropy         = "insufficient_entropy"
-	errInvalidTokenFormat          = "invalid_token"
-	errTokenSignatureMismatch      = "token_signature_mismatch"
-	errTokenExpired                = "token_expired"
-	errScopeNotGranted             = "scope_not_granted"
-	errTokenClaim                  = "token_claim"
-	errTokenInactive               = "token_inactive"
+	errRequestUnauthorized             = "request_unauthorized"
+	errRequestForbidden                = "request_forbidden"
+	errInvalidRequestName              = "invalid_request"
+	errUnauthorizedClientName          = "unauthorized_client"
+	errAccessDeniedName                = "access_denied"
+	errUnsupportedResponseTypeName     = "unsupported_response_type"
+	errInvalidScopeName                = "invalid_scope"
+	errServerErrorName                 = "server_error"
+	errTemporarilyUnavailableName      = "temporarily_unavailable"
+	errUnsupportedGrantTypeName        = "unsupported_grant_type"
+	errInvalidGrantName                = "invalid_grant"
+	errInvalidClientName               = "invalid_client"
+	UnknownErrorName                   = "unknown_error"
+	errNotFound                        = "not_found"
+	errInvalidState                    = "invalid_state"
+	errMisconfiguration                = "misconfiguration"
+	errInsufficientEntropy             = "insufficient_entropy"
+	errInvalidTokenFormat              = "invalid_token"
+	errTokenSignatureMismatch          = "token_signature_mismatch"
+	errTokenExpired                    = "token_expired"
+	errScopeNotGranted                 = "scope_not_granted"
+	errTokenClaim                      = "token_claim"
+	errTokenInactive                   = "token_inactive"
+	errInvalidTargetName               = "invalid_target"
+	errInvalidAuthorizationDetailsName = "invalid_authorization_details"
+	errInvalidDPoPProofName            = "invalid_dpop_proof"
+	errUseDPoPNonceName                = "use_dpop_nonce"
+	errUnsupportedTokenTypeName        = "unsupported_token_type"
+	errInvalidRedirectURIName          = "invalid_redirect_uri"
+	errInvalidClientMetadataName       = "invalid_client_metadata"
+	errInvalidSoftwareStatementName    = "invalid_software_statement"
+	errUnapprovedSoftwareStatementName = "unapproved_software_statement"
 )
 
 type RFC6749Error struct {
@@ -247,6 +265,78 @@ func ErrorToRFC6749Error(err error) *RFC6749Error {
 			Debug:       err.Error(),
 			Code:        http.StatusInternalServerError,
 		}
+	case ErrInvalidTarget:
+		return &RFC6749Error{
+			Name:        errInvalidTargetName,
+			Description: ErrInvalidTarget.Error(),
+			Debug:       err.Error(),
+			Hint:        "Make sure that the client is allowed to request the audience or resource.",
+			Code:        http.StatusBadRequest,
+		}
+	case ErrInvalidAuthorizationDetails:
+		return &RFC6749Error{
+			Name:        errInvalidAuthorizationDetailsName,
+			Description: ErrInvalidAuthorizationDetails.Error(),
+			Debug:       err.Error(),
+			Hint:        "Make sure that authorization_details is a JSON array of objects of supported types.",
+			Code:        http.StatusBadRequest,
+		}
+	case ErrInvalidDPoPProof:
+		return &RFC6749Error{
+			Name:        errInvalidDPoPProofName,
+			Description: ErrInvalidDPoPProof.Error(),
+			Debug:       err.Error(),
+			Hint:        "Make sure that the DPoP proof is a valid dpop+jwt signed with the key the token is bound to.",
+			Code:        http.StatusBadRequest,
+		}
+	case ErrUseDPoPNonce:
+		return &RFC6749Error{
+			Name:        errUseDPoPNonceName,
+			Description: ErrUseDPoPNonce.Error(),
+			Debug:       err.Error(),
+			Hint:        "Retry the request with a DPoP proof containing the nonce from the DPoP-Nonce header.",
+			Code:        http.StatusBadRequest,
+		}
+	case ErrUnsupportedTokenType:
+		return &RFC6749Error{
+			Name:        errUnsupportedTokenTypeName,
+			Description: ErrUnsupportedTokenType.Error(),
+			Debug:       err.Error(),
+			Hint:        "Make sure that token_type_hint is access_token, refresh_token or omitted.",
+			Code:        http.StatusBadRequest,
+		}
+	case ErrInvalidRedirectURI:
+		return &RFC6749Error{
+			Name:        errInvalidRedirectURIName,
+			Description: ErrInvalidRedirectURI.Error(),
+			Debug:       err.Error(),
+			Hint:        "Make sure that all redirect URIs are absolute, contain no fragment and use https unless they point to localhost.",
+			Code:        http.StatusBadRequest,
+		}
+	case ErrInvalidClientMetadata:
+		return &RFC6749Error{
+			Name:        errInvalidClientMetadataName,
+			Description: ErrInvalidClientMetadata.Error(),
+			Debug:       err.Error(),
+			Hint:        "Make sure that the grant types, response types and token endpoint authentication method are consistent.",
+			Code:        http.StatusBadRequest,
+		}
+	case ErrInvalidSoftwareStatement:
+		return &RFC6749Error{
+			Name:        errInvalidSoftwareStatementName,
+			Description: ErrInvalidSoftwareStatement.Error(),
+			Debug:       err.Error(),
+			Hint:        "Make sure that the software statement is a valid JWT signed by a trusted issuer and that it is sent if required.",
+			Code:        http.StatusBadRequest,
+		}
+	case ErrUnapprovedSoftwareStatement:
+		return &RFC6749Error{
+			Name:        errUnapprovedSoftwareStatementName,
+			Description: ErrUnapprovedSoftwareStatement.Error(),
+			Debug:       err.Error(),
+			Hint:        "Make sure that the software statement was issued by a trusted issuer.",
+			Code:        http.StatusBadRequest,
+		}
 	case ErrNotFound:
 		return &RFC6749Error{
 			Name:        errNotFound,
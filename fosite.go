@@ -69,4 +69,44 @@ type Fosite struct {
 	RevocationHandlers         RevocationHandlers
 	Hasher                     Hasher
 	ScopeStrategy              ScopeStrategy
+
+	// Issuer is the issuer identifier of the authorization server. It is returned in the "iss" member of
+	// introspection responses.
+	Issuer string
+
+	// ScopeRegistry is the registry of supported scopes. If set, requests containing other scopes are rejected and
+	// the registry's default scopes are requested if the scope parameter is omitted. If nil, any scope is accepted.
+	ScopeRegistry ScopeRegistry
+
+	// AudienceMatchingStrategy validates requested audiences against the client's audiences. Defaults to
+	// DefaultAudienceMatchingStrategy.
+	AudienceMatchingStrategy AudienceMatchingStrategy
+
+	// AuthorizationDetailTypes is the registry of supported authorization details types (RFC 9396). Requests
+	// containing authorization details of other types are rejected.
+	AuthorizationDetailTypes AuthorizationDetailTypes
+
+	// DPoPStrategy validates DPoP proofs (RFC 9449). If set, tokens requested with a DPoP proof are bound to the
+	// proof's key. If nil, DPoP proofs are ignored and bearer tokens are issued.
+	DPoPStrategy *DPoPStrategy
+
+	// JWTIntrospectionStrategy generates JWT introspection responses (RFC 9701) for clients that ask for them. If
+	// nil, introspection responses are always plain JSON.
+	JWTIntrospectionStrategy *JWTIntrospectionStrategy
+
+	// IntrospectionAuthorizer decides which tokens a client may introspect. If nil, every authenticated client may
+	// introspect every token.
+	IntrospectionAuthorizer IntrospectionAuthorizer
+
+	// SubjectIdentifierStrategy transforms the subject returned by introspection, e.g. into a pairwise subject
+	// identifier. It should be the strategy used for ID tokens. If nil, the session's subject is returned.
+	SubjectIdentifierStrategy SubjectIdentifierStrategy
+
+	// RedirectURIMatcher matches redirect URIs of clients which do not select a matcher, see
+	// ClientWithRedirectURIMatcher. Defaults to LoopbackRedirectURIMatcher.
+	RedirectURIMatcher RedirectURIMatcher
+
+	// RedirectURIMatchers are additional named matchers clients may select besides the built-in RedirectURIMatcherExact,
+	// RedirectURIMatcherLoopback and RedirectURIMatcherWildcard.
+	RedirectURIMatchers map[string]RedirectURIMatcher
 }
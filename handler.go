@@ -46,7 +46,10 @@ type TokenEndpointHandler interface {
 // grant (see Implementation Note). If the token passed to the request
 // is an access token, the server MAY revoke the respective refresh
 // token as well.
+//
+// The client is the authenticated client making the request. Handlers must not revoke tokens issued to other
+// clients and return ErrUnauthorizedClient instead.
 type RevocationHandler interface {
 	// RevokeToken handles access and refresh token revocation.
-	RevokeToken(ctx context.Context, token string, tokenType TokenType) error
+	RevokeToken(ctx context.Context, token string, tokenType TokenType, client Client) error
 }
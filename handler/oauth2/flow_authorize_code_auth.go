@@ -28,7 +28,18 @@ type AuthorizeExplicitGrantHandler struct {
 	// AccessTokenLifespan defines the lifetime of an access token.
 	AccessTokenLifespan time.Duration
 
+	// RefreshTokenLifespan defines the lifetime of a refresh token. Zero means no expiry.
+	RefreshTokenLifespan time.Duration
+
+	// RefreshTokenMaxLifespan defines the absolute lifetime of the grant, see RefreshTokenGrantHandler.
+	RefreshTokenMaxLifespan time.Duration
+
 	ScopeStrategy fosite.ScopeStrategy
+
+	// RedirectURIMatcher and RedirectURIMatchers select the matcher the redirect URI of a token request is matched
+	// with again, see fosite.GetRedirectURIMatcher. They should equal the settings of fosite.Fosite.
+	RedirectURIMatcher  fosite.RedirectURIMatcher
+	RedirectURIMatchers map[string]fosite.RedirectURIMatcher
 }
 
 func (c *AuthorizeExplicitGrantHandler) HandleAuthorizeEndpointRequest(ctx context.Context, ar fosite.AuthorizeRequester, resp fosite.AuthorizeResponder) error {
@@ -42,7 +53,7 @@ func (c *AuthorizeExplicitGrantHandler) HandleAuthorizeEndpointRequest(ctx conte
 	}
 
 	if !fosite.IsRedirectURISecure(ar.GetRedirectURI()) {
-		return errors.Wrap(fosite.ErrInvalidRequest, "Redirect URL is using an insecure protocol, http is only allowed for hosts with suffix `localhost`, for example: http://myapp.localhost/")
+		return errors.Wrap(fosite.ErrInvalidRequest, "Redirect URL is using an insecure protocol, http is only allowed for localhost and loopback IP addresses, for example: http://127.0.0.1:8080/")
 	}
 
 	client := ar.GetClient()
@@ -61,7 +72,8 @@ func (c *AuthorizeExplicitGrantHandler) IssueAuthorizeCode(ctx context.Context,
 		return errors.Wrap(fosite.ErrServerError, err.Error())
 	}
 
-	ar.GetSession().SetExpiresAt(fosite.AuthorizeCode, time.Now().Add(c.AuthCodeLifespan))
+	lifespan := fosite.GetEffectiveLifespan(ar.GetClient(), "authorization_code", fosite.AuthorizeCode, c.AuthCodeLifespan)
+	ar.GetSession().SetExpiresAt(fosite.AuthorizeCode, time.Now().Add(lifespan))
 	if err := c.AuthorizeCodeGrantStorage.CreateAuthorizeCodeSession(ctx, signature, ar); err != nil {
 		return errors.Wrap(fosite.ErrServerError, err.Error())
 	}
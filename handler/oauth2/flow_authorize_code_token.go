@@ -35,9 +35,6 @@ func (c *AuthorizeExplicitGrantHandler) HandleTokenEndpointRequest(ctx context.C
 		return errors.Wrap(fosite.ErrInvalidRequest, err.Error())
 	}
 
-	// Override scopes
-	request.SetRequestedScopes(authorizeRequest.GetRequestedScopes())
-
 	// The authorization server MUST ensure that the authorization code was issued to the authenticated
 	// confidential client, or if the client is public, ensure that the
 	// code was issued to "client_id" in the request,
@@ -52,15 +49,35 @@ func (c *AuthorizeExplicitGrantHandler) HandleTokenEndpointRequest(ctx context.C
 	forcedRedirectURI := authorizeRequest.GetRequestForm().Get("redirect_uri")
 	if forcedRedirectURI != "" && forcedRedirectURI != request.GetRequestForm().Get("redirect_uri") {
 		return errors.Wrap(fosite.ErrInvalidRequest, "Redirect URI mismatch")
+	} else if forcedRedirectURI != "" {
+		// The redirect URI must still be allowed by the client's matcher, e.g. if the client's redirect URIs
+		// changed since the authorization code was issued.
+		matcher, err := fosite.GetRedirectURIMatcher(request.GetClient(), c.RedirectURIMatcher, c.RedirectURIMatchers)
+		if err != nil {
+			return err
+		} else if _, err := fosite.MatchRedirectURI(forcedRedirectURI, request.GetClient(), matcher); err != nil {
+			return errors.Wrap(fosite.ErrInvalidGrant, "Redirect URI is not allowed for the client")
+		}
+	}
+
+	if err := grantScopes(authorizeRequest.GetGrantedScopes(), request, c.ScopeStrategy); err != nil {
+		return err
+	} else if err := grantAudience(authorizeRequest, request); err != nil {
+		return err
+	} else if err := grantAuthorizationDetails(authorizeRequest, request); err != nil {
+		return err
 	}
 
+	// Override scopes
+	request.SetRequestedScopes(authorizeRequest.GetRequestedScopes())
+
 	// Checking of POST client_id skipped, because:
 	// If the client type is confidential or the client was issued client
 	// credentials (or assigned other authentication requirements), the
 	// client MUST authenticate with the authorization server as described
 	// in Section 3.2.1.
 	request.SetSession(authorizeRequest.GetSession())
-	request.GetSession().SetExpiresAt(fosite.AccessToken, time.Now().Add(c.AccessTokenLifespan))
+	request.GetSession().SetExpiresAt(fosite.AccessToken, time.Now().Add(c.tokenLifespan(request, fosite.AccessToken)))
 	return nil
 }
 
@@ -81,10 +98,6 @@ func (c *AuthorizeExplicitGrantHandler) PopulateTokenEndpointResponse(ctx contex
 		return errors.Wrap(fosite.ErrInvalidRequest, err.Error())
 	}
 
-	for _, scope := range authorizeRequest.GetGrantedScopes() {
-		requester.GrantScope(scope)
-	}
-
 	access, accessSignature, err := c.AccessTokenStrategy.GenerateAccessToken(ctx, requester)
 	if err != nil {
 		return errors.Wrap(fosite.ErrServerError, err.Error())
@@ -92,6 +105,7 @@ func (c *AuthorizeExplicitGrantHandler) PopulateTokenEndpointResponse(ctx contex
 
 	var refresh, refreshSignature string
 	if authorizeRequest.GetGrantedScopes().Has("offline") {
+		startRefreshTokenGrant(requester.GetSession(), c.tokenLifespan(requester, fosite.RefreshToken), c.RefreshTokenMaxLifespan, time.Now())
 		refresh, refreshSignature, err = c.RefreshTokenStrategy.GenerateRefreshToken(ctx, requester)
 		if err != nil {
 			return errors.Wrap(fosite.ErrServerError, err.Error())
@@ -103,12 +117,21 @@ func (c *AuthorizeExplicitGrantHandler) PopulateTokenEndpointResponse(ctx contex
 	}
 
 	responder.SetAccessToken(access)
-	responder.SetTokenType("bearer")
-	responder.SetExpiresIn(getExpiresIn(requester, fosite.AccessToken, c.AccessTokenLifespan, time.Now()))
+	responder.SetTokenType(getTokenType(requester))
+	responder.SetExpiresIn(getExpiresIn(requester, fosite.AccessToken, c.tokenLifespan(requester, fosite.AccessToken), time.Now()))
 	responder.SetScopes(requester.GetGrantedScopes())
+	setAuthorizationDetails(requester, responder)
 	if refresh != "" {
 		responder.SetExtra("refresh_token", refresh)
 	}
 
 	return nil
 }
+
+func (c *AuthorizeExplicitGrantHandler) tokenLifespan(requester fosite.Requester, tokenType fosite.TokenType) time.Duration {
+	fallback := c.AccessTokenLifespan
+	if tokenType == fosite.RefreshToken {
+		fallback = c.RefreshTokenLifespan
+	}
+	return fosite.GetEffectiveLifespan(requester.GetClient(), "authorization_code", tokenType, fallback)
+}
@@ -134,6 +134,7 @@ func TestAuthorizeCode_HandleTokenEndpointRequest(t *testing.T) {
 		description string
 		setup       func()
 		expectErr   error
+		expect      func()
 	}{
 		{
 			description: "should fail because not responsible",
@@ -167,6 +168,7 @@ func TestAuthorizeCode_HandleTokenEndpointRequest(t *testing.T) {
 
 				areq.Client = &fosite.DefaultClient{ID: "foo"}
 				authreq.Scopes = fosite.Arguments{"a", "b"}
+				authreq.GrantedScopes = fosite.Arguments{"a", "b"}
 				authreq.Client = &fosite.DefaultClient{ID: "bar"}
 			},
 			expectErr: fosite.ErrInvalidRequest,
@@ -179,6 +181,27 @@ func TestAuthorizeCode_HandleTokenEndpointRequest(t *testing.T) {
 			},
 			expectErr: fosite.ErrInvalidRequest,
 		},
+		{
+			description: "should fail because the redirect uri is not allowed for the client",
+			setup: func() {
+				authreq.Form.Set("redirect_uri", "https://foo.example.com/cb")
+				areq.Form.Set("redirect_uri", "https://foo.example.com/cb")
+				areq.Client = &fosite.DefaultClient{ID: "foo", RedirectURIs: []string{"https://bar.example.com/cb"}}
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should pass because the client's matcher allows the redirect uri",
+			setup: func() {
+				authreq.Form.Set("redirect_uri", "https://pr-1.preview.example.com/cb")
+				areq.Form.Set("redirect_uri", "https://pr-1.preview.example.com/cb")
+				areq.Client = &fosite.DefaultClient{
+					ID:                 "foo",
+					RedirectURIs:       []string{"https://*.preview.example.com/cb"},
+					RedirectURIMatcher: fosite.RedirectURIMatcherWildcard,
+				}
+			},
+		},
 		{
 			description: "should pass (2)",
 			setup: func() {
@@ -187,10 +210,32 @@ func TestAuthorizeCode_HandleTokenEndpointRequest(t *testing.T) {
 				authreq.RequestedAt = time.Now().Add(time.Hour)
 			},
 		},
+		{
+			description: "should grant the requested subset of the granted scopes",
+			setup: func() {
+				areq.Scopes = fosite.Arguments{"b"}
+				areq.GrantedScopes = fosite.Arguments{}
+			},
+			expect: func() {
+				assert.Equal(t, fosite.Arguments{"b"}, areq.GrantedScopes)
+				assert.Equal(t, fosite.Arguments{"a", "b"}, areq.GrantScopes)
+				assert.Equal(t, fosite.Arguments{"a", "b"}, areq.Scopes)
+			},
+		},
+		{
+			description: "should fail because the requested scope was not granted",
+			setup: func() {
+				areq.Scopes = fosite.Arguments{"a", "c"}
+			},
+			expectErr: fosite.ErrInvalidScope,
+		},
 	} {
 		c.setup()
 		err := h.HandleTokenEndpointRequest(nil, areq)
 		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expect != nil {
+			c.expect()
+		}
 		t.Logf("Passed test case %d", k)
 	}
 }
@@ -38,7 +38,8 @@ func (c *ClientCredentialsGrantHandler) HandleTokenEndpointRequest(_ context.Con
 	}
 	// if the client is not public, he has already been authenticated by the access request handler.
 
-	request.GetSession().SetExpiresAt(fosite.AccessToken, time.Now().Add(c.AccessTokenLifespan))
+	lifespan := fosite.GetEffectiveLifespan(client, "client_credentials", fosite.AccessToken, c.AccessTokenLifespan)
+	request.GetSession().SetExpiresAt(fosite.AccessToken, time.Now().Add(lifespan))
 	return nil
 }
 
@@ -81,6 +81,7 @@ func TestClientCredentials_PopulateTokenEndpointResponse(t *testing.T) {
 		mock        func()
 		req         *http.Request
 		expectErr   error
+		expect      func()
 	}{
 		{
 			description: "should fail because not responsible",
@@ -107,10 +108,30 @@ func TestClientCredentials_PopulateTokenEndpointResponse(t *testing.T) {
 				store.EXPECT().CreateAccessTokenSession(nil, "bar", areq).Return(nil)
 			},
 		},
+		{
+			description: "should pass with the client's access token lifespan",
+			mock: func() {
+				areq.Client = &fosite.DefaultClientWithCustomTokenLifespans{
+					DefaultClient: &fosite.DefaultClient{GrantTypes: fosite.Arguments{"client_credentials"}},
+					TokenLifespans: map[string]map[fosite.TokenType]time.Duration{
+						"client_credentials": {fosite.AccessToken: 5 * time.Minute},
+					},
+				}
+				chgen.EXPECT().GenerateAccessToken(nil, areq).Return("tokenfoo.bar", "bar", nil)
+				store.EXPECT().CreateAccessTokenSession(nil, "bar", areq).Return(nil)
+			},
+			expect: func() {
+				assert.True(t, aresp.GetExtra("expires_in").(int64) <= 300)
+				assert.True(t, areq.GetSession().GetExpiresAt(fosite.AccessToken).Before(time.Now().Add(6*time.Minute)))
+			},
+		},
 	} {
 		c.mock()
 		err := h.PopulateTokenEndpointResponse(nil, areq, aresp)
 		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expect != nil {
+			c.expect()
+		}
 		t.Logf("Passed test case %d", k)
 	}
 }
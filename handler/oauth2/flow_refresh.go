@@ -20,8 +20,22 @@ type RefreshTokenGrantHandler struct {
 	// AccessTokenLifespan defines the lifetime of an access token.
 	AccessTokenLifespan time.Duration
 
-	// RefreshTokenLifespan sets how long an id token is going to be valid. Defaults to one hour.
+	// RefreshTokenLifespan defines how long a refresh token is valid after it was issued. Because every refresh issues
+	// a new refresh token, this is a sliding (idle) timeout for the grant. Zero means no expiry, negative values mean
+	// that the refresh token is not rotated.
 	RefreshTokenLifespan time.Duration
+
+	// RefreshTokenMaxLifespan defines the absolute lifetime of a grant, counted from the moment its first refresh
+	// token was issued. Refreshing never extends a refresh token beyond it. Zero or less means no absolute limit.
+	RefreshTokenMaxLifespan time.Duration
+
+	// ScopeStrategy is used to check that the scopes requested when refreshing were granted by the resource owner.
+	// Defaults to fosite.ExactScopeStrategy.
+	ScopeStrategy fosite.ScopeStrategy
+
+	// ScopeRegistry, if set, drops the granted scopes registered as refresh ineligible, see
+	// fosite.ScopeRegistry.IsRefreshEligible.
+	ScopeRegistry fosite.ScopeRegistry
 }
 
 // HandleTokenEndpointRequest implements https://tools.ietf.org/html/rfc6749#section-6
@@ -43,11 +57,9 @@ func (c *RefreshTokenGrantHandler) HandleTokenEndpointRequest(ctx context.Contex
 		return errors.Wrap(fosite.ErrInvalidRequest, err.Error())
 	} else if err != nil {
 		return errors.Wrap(fosite.ErrServerError, err.Error())
-	} else if c.RefreshTokenLifespan > 0 && originalRequest.GetRequestedAt().Add(c.RefreshTokenLifespan).Before(time.Now()) {
-		return errors.WithStack(fosite.ErrTokenExpired)
 	}
 
-	if !originalRequest.GetGrantedScopes().Has("offline") {
+	if !fosite.GetGrantScopes(originalRequest).Has("offline") {
 		return errors.Wrap(fosite.ErrScopeNotGranted, "The client is not allowed to use grant type refresh_token")
 
 	}
@@ -62,16 +74,59 @@ func (c *RefreshTokenGrantHandler) HandleTokenEndpointRequest(ctx context.Contex
 		return errors.Wrap(fosite.ErrInvalidRequest, "Client ID mismatch")
 	}
 
+	now := time.Now()
+	if exp := c.refreshTokenExpiresAt(originalRequest); !exp.IsZero() && exp.Before(now) {
+		return errors.Wrapf(fosite.ErrTokenExpired, "Refresh token expired at %s", exp)
+	}
+
+	// Refresh tokens issued to public clients are bound to the DPoP key they were requested with, see
+	// https://tools.ietf.org/html/rfc9449#section-5
+	if thumbprint := fosite.GetDPoPJWKThumbprint(originalRequest); thumbprint != "" && request.GetClient().IsPublic() && thumbprint != fosite.GetDPoPJWKThumbprint(request) {
+		return errors.Wrap(fosite.ErrInvalidDPoPProof, "The refresh token is bound to another DPoP key")
+	}
+
+	var granted fosite.Arguments
+	for _, scope := range fosite.GetGrantScopes(originalRequest) {
+		if c.ScopeRegistry.IsRefreshEligible(scope) {
+			granted = append(granted, scope)
+		}
+	}
+
 	request.SetSession(originalRequest.GetSession().Clone())
-	request.SetRequestedScopes(originalRequest.GetRequestedScopes())
-	for _, scope := range originalRequest.GetGrantedScopes() {
-		request.GrantScope(scope)
+	if err := grantScopes(granted, request, c.ScopeStrategy); err != nil {
+		return err
+	} else if err := grantAudience(originalRequest, request); err != nil {
+		return err
+	} else if err := grantAuthorizationDetails(originalRequest, request); err != nil {
+		return err
 	}
 
-	request.GetSession().SetExpiresAt(fosite.AccessToken, time.Now().Add(c.AccessTokenLifespan))
+	request.SetRequestedScopes(originalRequest.GetRequestedScopes())
+	request.GetSession().SetExpiresAt(fosite.AccessToken, now.Add(c.accessTokenLifespan(request)))
+	setRefreshTokenExpiresAt(request.GetSession(), originalRequest.GetRequestedAt(), c.refreshTokenLifespan(request), c.RefreshTokenMaxLifespan, now)
 	return nil
 }
 
+func (c *RefreshTokenGrantHandler) refreshTokenExpiresAt(original fosite.Requester) time.Time {
+	if exp := original.GetSession().GetExpiresAt(fosite.RefreshToken); !exp.IsZero() {
+		return exp
+	}
+
+	// The refresh token was issued before its expiry was tracked in the session.
+	if lifespan := c.refreshTokenLifespan(original); lifespan > 0 {
+		return original.GetRequestedAt().Add(lifespan)
+	}
+	return time.Time{}
+}
+
+func (c *RefreshTokenGrantHandler) accessTokenLifespan(requester fosite.Requester) time.Duration {
+	return fosite.GetEffectiveLifespan(requester.GetClient(), "refresh_token", fosite.AccessToken, c.AccessTokenLifespan)
+}
+
+func (c *RefreshTokenGrantHandler) refreshTokenLifespan(requester fosite.Requester) time.Duration {
+	return fosite.GetEffectiveLifespan(requester.GetClient(), "refresh_token", fosite.RefreshToken, c.RefreshTokenLifespan)
+}
+
 // PopulateTokenEndpointResponse implements https://tools.ietf.org/html/rfc6749#section-6
 func (c *RefreshTokenGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) error {
 	if !requester.GetGrantTypes().Exact("refresh_token") {
@@ -94,15 +149,16 @@ func (c *RefreshTokenGrantHandler) PopulateTokenEndpointResponse(ctx context.Con
 	}
 
 	responder.SetAccessToken(accessToken)
-	responder.SetTokenType("bearer")
-	responder.SetExpiresIn(getExpiresIn(requester, fosite.AccessToken, c.AccessTokenLifespan, time.Now()))
+	responder.SetTokenType(getTokenType(requester))
+	responder.SetExpiresIn(getExpiresIn(requester, fosite.AccessToken, c.accessTokenLifespan(requester), time.Now()))
 	responder.SetScopes(requester.GetGrantedScopes())
+	setAuthorizationDetails(requester, responder)
 	responder.SetExtra("refresh_token", refreshToken)
 	return nil
 }
 
 func (c *RefreshTokenGrantHandler) getRefreshTokenAndSignature(ctx context.Context, requester fosite.AccessRequester) (refreshToken string, refreshSignature string, err error) {
-	if c.RefreshTokenLifespan < 0 {
+	if c.refreshTokenLifespan(requester) < 0 {
 		refreshToken = requester.GetRequestForm().Get("refresh_token")
 		refreshSignature = c.RefreshTokenStrategy.RefreshTokenSignature(refreshToken)
 	} else {
@@ -101,6 +101,7 @@ func TestRefreshFlow_HandleTokenEndpointRequest(t *testing.T) {
 			description: "should pass with permanent refresh token",
 			setup: func() {
 				areq.SetSession(nil)
+				areq.Scopes = fosite.Arguments{}
 				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
 					Client:        &fosite.DefaultClient{ID: "foo"},
 					GrantedScopes: fosite.Arguments{"foo", "offline"},
@@ -135,6 +136,223 @@ func TestRefreshFlow_HandleTokenEndpointRequest(t *testing.T) {
 			},
 			expectErr: fosite.ErrTokenExpired,
 		},
+		{
+			description: "should reject refresh token that expired according to the session",
+			setup: func() {
+				h.RefreshTokenLifespan = time.Hour
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"foo", "offline"},
+					Session: &fosite.DefaultSession{ExpiresAt: map[fosite.TokenType]time.Time{
+						fosite.RefreshToken: time.Now().Add(-time.Minute),
+					}},
+					RequestedAt: time.Now(),
+				}, nil)
+			},
+			expectErr: fosite.ErrTokenExpired,
+		},
+		{
+			description: "should slide refresh token expiry but not beyond the absolute expiry of the grant",
+			setup: func() {
+				areq.SetSession(nil)
+				areq.Scopes = fosite.Arguments{}
+				h.RefreshTokenLifespan = time.Hour
+				h.RefreshTokenMaxLifespan = 24 * time.Hour
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"foo", "offline"},
+					Session: &fosite.DefaultSession{ExpiresAt: map[fosite.TokenType]time.Time{
+						fosite.RefreshToken: time.Now().Add(time.Minute),
+						refreshTokenGrant:   time.Now().Add(30 * time.Minute),
+					}},
+					RequestedAt: time.Now().Add(-time.Hour),
+				}, nil)
+			},
+			expect: func() {
+				exp := areq.GetSession().GetExpiresAt(fosite.RefreshToken)
+				assert.Equal(t, areq.GetSession().GetExpiresAt(refreshTokenGrant), exp)
+				assert.True(t, exp.After(time.Now().Add(29*time.Minute)))
+				assert.True(t, exp.Before(time.Now().Add(31*time.Minute)))
+			},
+		},
+		{
+			description: "should extend refresh token expiry by the sliding lifespan",
+			setup: func() {
+				areq.SetSession(nil)
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"foo", "offline"},
+					Session: &fosite.DefaultSession{ExpiresAt: map[fosite.TokenType]time.Time{
+						fosite.RefreshToken: time.Now().Add(time.Minute),
+						refreshTokenGrant:   time.Now().Add(12 * time.Hour),
+					}},
+					RequestedAt: time.Now().Add(-time.Hour),
+				}, nil)
+			},
+			expect: func() {
+				exp := areq.GetSession().GetExpiresAt(fosite.RefreshToken)
+				assert.True(t, exp.After(time.Now().Add(59*time.Minute)))
+				assert.True(t, exp.Before(time.Now().Add(61*time.Minute)))
+			},
+		},
+		{
+			description: "should reject refresh token of a public client that is bound to another DPoP key",
+			setup: func() {
+				areq.Client = &fosite.DefaultClient{ID: "foo", GrantTypes: fosite.Arguments{"refresh_token"}, Public: true}
+				areq.SetSession(nil)
+				areq.SetDPoPJWKThumbprint("other-thumbprint")
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:            &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes:     fosite.Arguments{"foo", "offline"},
+					Session:           sess,
+					RequestedAt:       time.Now(),
+					DPoPJWKThumbprint: "thumbprint",
+				}, nil)
+			},
+			expectErr: fosite.ErrInvalidDPoPProof,
+		},
+		{
+			description: "should pass with refresh token of a public client that is bound to the same DPoP key",
+			setup: func() {
+				areq.SetSession(nil)
+				areq.SetDPoPJWKThumbprint("thumbprint")
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:            &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes:     fosite.Arguments{"foo", "offline"},
+					Session:           sess,
+					RequestedAt:       time.Now(),
+					DPoPJWKThumbprint: "thumbprint",
+				}, nil)
+			},
+		},
+		{
+			description: "should keep the subject, expiry and custom claims of the session",
+			setup: func() {
+				areq.SetSession(nil)
+				areq.Scopes = fosite.Arguments{}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"foo", "offline"},
+					Session: &fosite.DefaultSession{
+						Subject: "peter",
+						ExpiresAt: map[fosite.TokenType]time.Time{
+							fosite.RefreshToken: time.Now().Add(time.Minute),
+							refreshTokenGrant:   time.Now().Add(time.Hour),
+						},
+						Extra: map[string]interface{}{
+							"roles":  []interface{}{"admin", "user"},
+							"tenant": map[string]interface{}{"id": "acme"},
+						},
+					},
+					RequestedAt: time.Now(),
+				}, nil)
+			},
+			expect: func() {
+				session := areq.GetSession().(*fosite.DefaultSession)
+				assert.Equal(t, "peter", session.Subject)
+				assert.False(t, session.GetExpiresAt(fosite.AccessToken).IsZero())
+				assert.False(t, session.GetExpiresAt(refreshTokenGrant).IsZero())
+				assert.Equal(t, []interface{}{"admin", "user"}, session.Extra["roles"])
+				assert.Equal(t, map[string]interface{}{"id": "acme"}, session.Extra["tenant"])
+			},
+		},
+		{
+			description: "should grant the requested subset of the granted scopes",
+			setup: func() {
+				areq.SetSession(nil)
+				areq.Scopes = fosite.Arguments{"foo"}
+				areq.GrantedScopes = fosite.Arguments{}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"foo", "bar", "offline"},
+					Scopes:        fosite.Arguments{"foo", "bar", "offline"},
+					Session:       sess,
+					RequestedAt:   time.Now(),
+				}, nil)
+			},
+			expect: func() {
+				assert.Equal(t, fosite.Arguments{"foo", "offline"}, areq.GrantedScopes)
+				assert.Equal(t, fosite.Arguments{"foo", "bar", "offline"}, areq.GrantScopes)
+				assert.Equal(t, fosite.Arguments{"foo", "bar", "offline"}, areq.Scopes)
+			},
+		},
+		{
+			description: "should fail because the requested scope was not granted",
+			setup: func() {
+				areq.SetSession(nil)
+				areq.Scopes = fosite.Arguments{"foo", "baz"}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"foo", "bar", "offline"},
+					Session:       sess,
+					RequestedAt:   time.Now(),
+				}, nil)
+			},
+			expectErr: fosite.ErrInvalidScope,
+		},
+		{
+			description: "should grant a scope of the original grant which was not granted by the previous refresh",
+			setup: func() {
+				areq.SetSession(nil)
+				areq.Scopes = fosite.Arguments{"bar"}
+				areq.GrantedScopes = fosite.Arguments{}
+				areq.GrantScopes = nil
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"foo", "offline"},
+					GrantScopes:   fosite.Arguments{"foo", "bar", "offline"},
+					Session:       sess,
+					RequestedAt:   time.Now(),
+				}, nil)
+			},
+			expect: func() {
+				assert.Equal(t, fosite.Arguments{"bar", "offline"}, areq.GrantedScopes)
+				assert.Equal(t, fosite.Arguments{"foo", "bar", "offline"}, areq.GrantScopes)
+			},
+		},
+		{
+			description: "should drop granted scopes which are not refresh eligible",
+			setup: func() {
+				areq.SetSession(nil)
+				areq.Scopes = fosite.Arguments{}
+				areq.GrantedScopes = fosite.Arguments{}
+				h.ScopeRegistry = fosite.ScopeRegistry{
+					"foo": {},
+					"bar": {RefreshIneligible: true},
+				}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"foo.read", "bar", "baz", "offline"},
+					Session:       sess,
+					RequestedAt:   time.Now(),
+				}, nil)
+			},
+			expect: func() {
+				assert.Equal(t, fosite.Arguments{"foo.read", "baz", "offline"}, areq.GrantedScopes)
+			},
+		},
+		{
+			description: "should keep granted scopes which are registered with a description only",
+			setup: func() {
+				areq.SetSession(nil)
+				areq.Scopes = fosite.Arguments{}
+				areq.GrantedScopes = fosite.Arguments{}
+				h.ScopeRegistry = fosite.ScopeRegistry{
+					"openid":  {Description: "Sign you in"},
+					"offline": {Description: "Keep you signed in"},
+					"photos":  {Description: "Access your photos"},
+				}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.Arguments{"openid", "photos.read", "offline"},
+					Session:       sess,
+					RequestedAt:   time.Now(),
+				}, nil)
+			},
+			expect: func() {
+				assert.Equal(t, fosite.Arguments{"openid", "photos.read", "offline"}, areq.GrantedScopes)
+			},
+		},
 	} {
 		c.setup()
 		err := h.HandleTokenEndpointRequest(nil, areq)
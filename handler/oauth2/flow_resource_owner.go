@@ -2,6 +2,7 @@ package oauth2
 
 import (
 	"fmt"
+	"time"
 
 	"context"
 
@@ -16,6 +17,12 @@ type ResourceOwnerPasswordCredentialsGrantHandler struct {
 	RefreshTokenStrategy RefreshTokenStrategy
 	ScopeStrategy        fosite.ScopeStrategy
 
+	// RefreshTokenLifespan defines the lifetime of a refresh token. Zero means no expiry.
+	RefreshTokenLifespan time.Duration
+
+	// RefreshTokenMaxLifespan defines the absolute lifetime of the grant, see RefreshTokenGrantHandler.
+	RefreshTokenMaxLifespan time.Duration
+
 	*HandleHelper
 }
 
@@ -62,6 +69,8 @@ func (c *ResourceOwnerPasswordCredentialsGrantHandler) PopulateTokenEndpointResp
 	var refresh, refreshSignature string
 	if requester.GetGrantedScopes().Has("offline") {
 		var err error
+		lifespan := fosite.GetEffectiveLifespan(requester.GetClient(), "password", fosite.RefreshToken, c.RefreshTokenLifespan)
+		startRefreshTokenGrant(requester.GetSession(), lifespan, c.RefreshTokenMaxLifespan, time.Now())
 		refresh, refreshSignature, err = c.RefreshTokenStrategy.GenerateRefreshToken(ctx, requester)
 		if err != nil {
 			return errors.Wrap(fosite.ErrServerError, err.Error())
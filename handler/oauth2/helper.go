@@ -6,6 +6,7 @@ import (
 	"context"
 
 	"github.com/ory/fosite"
+	"github.com/pkg/errors"
 )
 
 type HandleHelper struct {
@@ -15,6 +16,9 @@ type HandleHelper struct {
 }
 
 func (h *HandleHelper) IssueAccessToken(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) error {
+	lifespan := fosite.GetEffectiveLifespan(requester.GetClient(), getGrantType(requester), fosite.AccessToken, h.AccessTokenLifespan)
+	requester.GetSession().SetExpiresAt(fosite.AccessToken, time.Now().Add(lifespan))
+
 	token, signature, err := h.AccessTokenStrategy.GenerateAccessToken(ctx, requester)
 	if err != nil {
 		return err
@@ -23,12 +27,140 @@ func (h *HandleHelper) IssueAccessToken(ctx context.Context, requester fosite.Ac
 	}
 
 	responder.SetAccessToken(token)
-	responder.SetTokenType("bearer")
-	responder.SetExpiresIn(getExpiresIn(requester, fosite.AccessToken, h.AccessTokenLifespan, time.Now()))
+	responder.SetTokenType(getTokenType(requester))
+	responder.SetExpiresIn(getExpiresIn(requester, fosite.AccessToken, lifespan, time.Now()))
 	responder.SetScopes(requester.GetGrantedScopes())
+	setAuthorizationDetails(requester, responder)
+	return nil
+}
+
+// grantScopes grants the scopes requested at the token endpoint, or all scopes of the grant if none were requested.
+// As defined in https://tools.ietf.org/html/rfc6749#section-6, requested scopes must have been granted by the resource
+// owner. The scope "offline" is kept because it belongs to the grant rather than the issued tokens. The grant's scopes
+// are remembered so that later refreshes may request any of them again.
+func grantScopes(granted fosite.Arguments, requester fosite.Requester, strategy fosite.ScopeStrategy) error {
+	if strategy == nil {
+		strategy = fosite.ExactScopeStrategy
+	}
+
+	requested := requester.GetRequestedScopes()
+	if len(requested) == 0 {
+		requested = granted
+	}
+
+	for _, scope := range requested {
+		if !strategy(granted, scope) {
+			return errors.Wrapf(fosite.ErrInvalidScope, "The scope %s was not granted by the resource owner", scope)
+		}
+	}
+
+	for _, scope := range requested {
+		requester.GrantScope(scope)
+	}
+	if granted.Has("offline") {
+		requester.GrantScope("offline")
+	}
+	if gr, ok := requester.(fosite.GrantScopesRequester); ok {
+		gr.SetGrantScopes(granted)
+	}
+	return nil
+}
+
+// grantAudience grants the audiences requested at the token endpoint, or all audiences granted by the original
+// request if none were requested. As defined in https://tools.ietf.org/html/rfc8707#section-2.2, requested audiences
+// must have been granted by the original request.
+func grantAudience(original, requester fosite.Requester) error {
+	granted := original.GetGrantedAudience()
+	requested := requester.GetRequestedAudience()
+	if len(requested) == 0 {
+		requested = granted
+	}
+
+	for _, audience := range requested {
+		if !fosite.ExactAudienceMatchingStrategy(granted, audience) {
+			return errors.Wrapf(fosite.ErrInvalidTarget, "The audience %s was not granted by the resource owner", audience)
+		}
+		requester.GrantAudience(audience)
+	}
+	return nil
+}
+
+// grantAuthorizationDetails grants the authorization details requested at the token endpoint, or all authorization
+// details granted by the original request if none were requested. As defined in
+// https://tools.ietf.org/html/rfc9396#section-6.1, requested authorization details must have been granted by the
+// original request.
+func grantAuthorizationDetails(original, requester fosite.Requester) error {
+	granted := original.GetGrantedAuthorizationDetails()
+	requested := requester.GetRequestedAuthorizationDetails()
+	if len(requested) == 0 {
+		requested = granted
+	}
+
+	for _, detail := range requested {
+		if !granted.Has(detail) {
+			return errors.Wrapf(fosite.ErrInvalidAuthorizationDetails, "The authorization details of type %s were not granted by the resource owner", detail.GetType())
+		}
+		requester.GrantAuthorizationDetail(detail)
+	}
 	return nil
 }
 
+// setAuthorizationDetails adds the granted authorization details to the token response as defined in
+// https://tools.ietf.org/html/rfc9396#section-7
+func setAuthorizationDetails(requester fosite.Requester, responder fosite.AccessResponder) {
+	if details := requester.GetGrantedAuthorizationDetails(); len(details) > 0 {
+		responder.SetExtra("authorization_details", details)
+	}
+}
+
+// getTokenType returns "DPoP" if the requester's tokens are bound to a DPoP key as defined in
+// https://tools.ietf.org/html/rfc9449#section-5, and "bearer" otherwise.
+func getTokenType(requester fosite.Requester) string {
+	if fosite.GetDPoPJWKThumbprint(requester) != "" {
+		return "DPoP"
+	}
+	return "bearer"
+}
+
+// getGrantType returns the grant type of a token endpoint request, or an empty string if it does not have exactly one.
+func getGrantType(requester fosite.AccessRequester) string {
+	if len(requester.GetGrantTypes()) != 1 {
+		return ""
+	}
+	return requester.GetGrantTypes()[0]
+}
+
+// refreshTokenGrant is the session key under which the absolute expiry of a refresh token grant is tracked. Unlike the
+// expiry of the refresh token itself, it is carried over unchanged when the refresh token is rotated.
+const refreshTokenGrant fosite.TokenType = "refresh_token_grant"
+
+// startRefreshTokenGrant tracks the expiry of the first refresh token of a new grant in the session.
+func startRefreshTokenGrant(session fosite.Session, lifespan, maxLifespan time.Duration, now time.Time) {
+	session.SetExpiresAt(refreshTokenGrant, time.Time{})
+	setRefreshTokenExpiresAt(session, now, lifespan, maxLifespan, now)
+}
+
+// setRefreshTokenExpiresAt tracks the expiry of a refresh token in the session. The refresh token expires lifespan
+// after now (sliding expiry) but never later than maxLifespan after grantedAt (absolute expiry). Lifespans of zero or
+// less disable the respective limit.
+func setRefreshTokenExpiresAt(session fosite.Session, grantedAt time.Time, lifespan, maxLifespan time.Duration, now time.Time) {
+	grantExp := session.GetExpiresAt(refreshTokenGrant)
+	if grantExp.IsZero() && maxLifespan > 0 {
+		grantExp = grantedAt.Add(maxLifespan)
+	}
+
+	var exp time.Time
+	if lifespan > 0 {
+		exp = now.Add(lifespan)
+	}
+	if !grantExp.IsZero() && (exp.IsZero() || grantExp.Before(exp)) {
+		exp = grantExp
+	}
+
+	session.SetExpiresAt(refreshTokenGrant, grantExp)
+	session.SetExpiresAt(fosite.RefreshToken, exp)
+}
+
 func getExpiresIn(r fosite.Requester, key fosite.TokenType, defaultLifespan time.Duration, now time.Time) time.Duration {
 	if r.GetSession().GetExpiresAt(key).IsZero() {
 		return defaultLifespan
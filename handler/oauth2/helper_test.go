@@ -22,6 +22,41 @@ func TestGetExpiresIn(t *testing.T) {
 	assert.Equal(t, time.Hour, getExpiresIn(r, fosite.AccessToken, time.Millisecond, now))
 }
 
+func TestSetRefreshTokenExpiresAt(t *testing.T) {
+	now := time.Now()
+	for k, c := range []struct {
+		lifespan    time.Duration
+		maxLifespan time.Duration
+		grantedAt   time.Time
+		grantExp    time.Time
+		expect      time.Time
+	}{
+		{expect: time.Time{}},
+		{lifespan: time.Hour, expect: now.Add(time.Hour)},
+		{maxLifespan: time.Hour, grantedAt: now.Add(-time.Minute), expect: now.Add(time.Hour - time.Minute)},
+		{lifespan: time.Hour, maxLifespan: 24 * time.Hour, grantedAt: now, expect: now.Add(time.Hour)},
+		{lifespan: time.Hour, maxLifespan: 24 * time.Hour, grantedAt: now.Add(-23 * time.Hour), expect: now.Add(time.Hour)},
+		{lifespan: time.Hour, maxLifespan: 24 * time.Hour, grantedAt: now.Add(-24 * time.Hour), expect: now},
+		{lifespan: time.Hour, maxLifespan: time.Hour, grantExp: now.Add(time.Minute), expect: now.Add(time.Minute)},
+		{lifespan: -time.Hour, grantExp: now.Add(time.Minute), expect: now.Add(time.Minute)},
+	} {
+		sess := &fosite.DefaultSession{}
+		sess.SetExpiresAt(refreshTokenGrant, c.grantExp)
+		setRefreshTokenExpiresAt(sess, c.grantedAt, c.lifespan, c.maxLifespan, now)
+		assert.Equal(t, c.expect, sess.GetExpiresAt(fosite.RefreshToken), "Case %d", k)
+	}
+}
+
+func TestStartRefreshTokenGrant(t *testing.T) {
+	now := time.Now()
+	sess := &fosite.DefaultSession{}
+	sess.SetExpiresAt(refreshTokenGrant, now.Add(-time.Hour))
+
+	startRefreshTokenGrant(sess, time.Hour, 24*time.Hour, now)
+	assert.Equal(t, now.Add(24*time.Hour), sess.GetExpiresAt(refreshTokenGrant))
+	assert.Equal(t, now.Add(time.Hour), sess.GetExpiresAt(fosite.RefreshToken))
+}
+
 func TestIssueAccessToken(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	areq := &fosite.AccessRequest{}
@@ -70,3 +105,40 @@ func TestIssueAccessToken(t *testing.T) {
 		}
 	}
 }
+
+func TestGrantAuthorizationDetails(t *testing.T) {
+	read := fosite.AuthorizationDetail{"type": "account_information", "actions": []interface{}{"read"}}
+	pay := fosite.AuthorizationDetail{"type": "payment_initiation", "instructedAmount": "45.00"}
+	original := fosite.NewRequest()
+	original.GrantAuthorizationDetail(read)
+	original.GrantAuthorizationDetail(pay)
+
+	for k, c := range []struct {
+		requested fosite.AuthorizationDetails
+		expect    fosite.AuthorizationDetails
+		expectErr error
+	}{
+		{requested: fosite.AuthorizationDetails{}, expect: fosite.AuthorizationDetails{read, pay}},
+		{requested: fosite.AuthorizationDetails{pay}, expect: fosite.AuthorizationDetails{pay}},
+		{
+			requested: fosite.AuthorizationDetails{{"type": "payment_initiation", "instructedAmount": "4500.00"}},
+			expectErr: fosite.ErrInvalidAuthorizationDetails,
+		},
+	} {
+		requester := fosite.NewRequest()
+		requester.SetRequestedAuthorizationDetails(c.requested)
+		err := grantAuthorizationDetails(original, requester)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s", k, err, c.expectErr)
+		if c.expectErr == nil {
+			assert.Equal(t, c.expect, requester.GetGrantedAuthorizationDetails(), "(%d)", k)
+		}
+	}
+}
+
+func TestGetTokenType(t *testing.T) {
+	r := fosite.NewRequest()
+	assert.Equal(t, "bearer", getTokenType(r))
+
+	r.SetDPoPJWKThumbprint("thumbprint")
+	assert.Equal(t, "DPoP", getTokenType(r))
+}
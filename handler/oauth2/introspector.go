@@ -61,6 +61,7 @@ func (c *CoreValidator) introspectAccessToken(ctx context.Context, token string,
 	}
 
 	accessRequest.Merge(or)
+	fosite.SetIntrospectedTokenType(accessRequest, fosite.AccessToken)
 	return nil
 }
 
@@ -79,5 +80,6 @@ func (c *CoreValidator) introspectRefreshToken(ctx context.Context, token string
 	}
 
 	accessRequest.Merge(or)
+	fosite.SetIntrospectedTokenType(accessRequest, fosite.RefreshToken)
 	return nil
 }
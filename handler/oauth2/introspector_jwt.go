@@ -34,5 +34,6 @@ func (v *StatelessJWTValidator) IntrospectToken(ctx context.Context, token strin
 	}
 
 	accessRequest.Merge(or)
+	fosite.SetIntrospectedTokenType(accessRequest, fosite.AccessToken)
 	return nil
 }
@@ -97,6 +97,7 @@ func TestIntrospectJWT(t *testing.T) {
 
 		if err == nil {
 			assert.Equal(t, "peter", areq.Session.GetSubject())
+			assert.NotEmpty(t, areq.Session.(fosite.TokenIDSession).GetTokenID())
 		}
 
 		t.Logf("Passed test case %d", k)
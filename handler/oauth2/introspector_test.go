@@ -73,4 +73,6 @@ func TestIntrospectToken(t *testing.T) {
 		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
 		t.Logf("Passed test case %d", k)
 	}
+
+	assert.Equal(t, fosite.AccessToken, areq.GetIntrospectedTokenType())
 }
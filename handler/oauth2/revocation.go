@@ -4,47 +4,130 @@ import (
 	"context"
 
 	"github.com/ory/fosite"
+	"github.com/pkg/errors"
 )
 
 type TokenRevocationHandler struct {
 	TokenRevocationStorage TokenRevocationStorage
 	RefreshTokenStrategy   RefreshTokenStrategy
 	AccessTokenStrategy    AccessTokenStrategy
+
+	// AuthorizeCodeStrategy enables the revocation of authorization codes if TokenRevocationStorage also implements
+	// AuthorizeCodeStorage.
+	AuthorizeCodeStrategy AuthorizeCodeStrategy
+}
+
+// openIDConnectSessionRevocationStorage is implemented by storages that keep OpenID Connect sessions, see
+// openid.OpenIDConnectRequestStorage. These sessions are deleted when the authorization code is revoked.
+type openIDConnectSessionRevocationStorage interface {
+	DeleteOpenIDConnectSession(ctx context.Context, authorizeCode string) error
+}
+
+// openIDConnectSessionBulkRevocationStorage is implemented by storages that delete OpenID Connect sessions in bulk,
+// see openid.OpenIDConnectSessionBulkRevocationStorage.
+type openIDConnectSessionBulkRevocationStorage interface {
+	DeleteOpenIDConnectSessions(ctx context.Context, filter fosite.BulkRevocationFilter) error
 }
 
 // RevokeToken implements https://tools.ietf.org/html/rfc7009#section-2.1
 // The token type hint indicates which token type check should be performed first.
-func (r *TokenRevocationHandler) RevokeToken(ctx context.Context, token string, tokenType fosite.TokenType) error {
-	discoveryFuncs := []func() (request fosite.Requester, err error){
-		func() (request fosite.Requester, err error) {
-			// Refresh token
+//
+// The token is only revoked if it was issued to the client making the request, otherwise
+// fosite.ErrUnauthorizedClient is returned. Hints for token types that can not be revoked result in
+// fosite.ErrUnsupportedTokenType.
+func (r *TokenRevocationHandler) RevokeToken(ctx context.Context, token string, tokenType fosite.TokenType, client fosite.Client) error {
+	discoveryFuncs := map[fosite.TokenType]func() (request fosite.Requester, err error){
+		fosite.RefreshToken: func() (request fosite.Requester, err error) {
 			signature := r.RefreshTokenStrategy.RefreshTokenSignature(token)
 			return r.TokenRevocationStorage.GetRefreshTokenSession(ctx, signature, nil)
 		},
-		func() (request fosite.Requester, err error) {
-			// Access token
+		fosite.AccessToken: func() (request fosite.Requester, err error) {
 			signature := r.AccessTokenStrategy.AccessTokenSignature(token)
 			return r.TokenRevocationStorage.GetAccessTokenSession(ctx, signature, nil)
 		},
 	}
+	order := []fosite.TokenType{fosite.RefreshToken, fosite.AccessToken}
+
+	codeStorage, ok := r.TokenRevocationStorage.(AuthorizeCodeStorage)
+	if ok && r.AuthorizeCodeStrategy != nil {
+		discoveryFuncs[fosite.AuthorizeCode] = func() (request fosite.Requester, err error) {
+			signature := r.AuthorizeCodeStrategy.AuthorizeCodeSignature(token)
+			return codeStorage.GetAuthorizeCodeSession(ctx, signature, nil)
+		}
+		order = append(order, fosite.AuthorizeCode)
+	}
 
 	// Token type hinting
-	if tokenType == fosite.AccessToken {
-		discoveryFuncs[0], discoveryFuncs[1] = discoveryFuncs[1], discoveryFuncs[0]
+	if tokenType != "" {
+		if _, ok := discoveryFuncs[tokenType]; !ok {
+			return errors.Wrapf(fosite.ErrUnsupportedTokenType, "Revocation of token type %s is not supported", tokenType)
+		}
+
+		hinted := []fosite.TokenType{tokenType}
+		for _, t := range order {
+			if t != tokenType {
+				hinted = append(hinted, t)
+			}
+		}
+		order = hinted
 	}
 
 	var ar fosite.Requester
 	var err error
-	if ar, err = discoveryFuncs[0](); err != nil {
-		ar, err = discoveryFuncs[1]()
+	var found fosite.TokenType
+	for _, t := range order {
+		if ar, err = discoveryFuncs[t](); err == nil {
+			found = t
+			break
+		}
 	}
 	if err != nil {
 		return err
 	}
 
+	if ar.GetClient() == nil || ar.GetClient().GetID() != client.GetID() {
+		return errors.Wrap(fosite.ErrUnauthorizedClient, "The token was not issued to the client making the revocation request")
+	}
+
+	if found == fosite.AuthorizeCode {
+		if err := codeStorage.DeleteAuthorizeCodeSession(ctx, r.AuthorizeCodeStrategy.AuthorizeCodeSignature(token)); err != nil {
+			return errors.Wrap(fosite.ErrServerError, err.Error())
+		}
+
+		if oidcStorage, ok := r.TokenRevocationStorage.(openIDConnectSessionRevocationStorage); ok {
+			if err := oidcStorage.DeleteOpenIDConnectSession(ctx, token); err != nil && errors.Cause(err) != fosite.ErrNotFound {
+				return errors.Wrap(fosite.ErrServerError, err.Error())
+			}
+		}
+	}
+
 	requestID := ar.GetID()
-	r.TokenRevocationStorage.RevokeRefreshToken(ctx, requestID)
-	r.TokenRevocationStorage.RevokeAccessToken(ctx, requestID)
+	if err := r.TokenRevocationStorage.RevokeRefreshToken(ctx, requestID); err != nil && errors.Cause(err) != fosite.ErrNotFound {
+		return errors.Wrap(fosite.ErrServerError, err.Error())
+	} else if err := r.TokenRevocationStorage.RevokeAccessToken(ctx, requestID); err != nil && errors.Cause(err) != fosite.ErrNotFound {
+		return errors.Wrap(fosite.ErrServerError, err.Error())
+	}
+
+	return nil
+}
+
+// RevokeTokens revokes all tokens matching the filter if TokenRevocationStorage implements
+// BulkTokenRevocationStorage. OpenID Connect sessions are deleted as well if the storage supports it.
+func (r *TokenRevocationHandler) RevokeTokens(ctx context.Context, filter fosite.BulkRevocationFilter) error {
+	storage, ok := r.TokenRevocationStorage.(BulkTokenRevocationStorage)
+	if !ok {
+		return errors.WithStack(fosite.ErrUnknownRequest)
+	}
+
+	if err := storage.RevokeTokens(ctx, filter); err != nil {
+		return errors.Wrap(fosite.ErrServerError, err.Error())
+	}
+
+	if oidcStorage, ok := r.TokenRevocationStorage.(openIDConnectSessionBulkRevocationStorage); ok {
+		if err := oidcStorage.DeleteOpenIDConnectSessions(ctx, filter); err != nil {
+			return errors.Wrap(fosite.ErrServerError, err.Error())
+		}
+	}
 
 	return nil
 }
@@ -2,6 +2,8 @@ package oauth2
 
 import (
 	"context"
+
+	"github.com/ory/fosite"
 )
 
 // TokenRevocationStorage provides the storage implementation
@@ -26,3 +28,10 @@ type TokenRevocationStorage interface {
 	// token as well.
 	RevokeAccessToken(ctx context.Context, requestID string) error
 }
+
+// BulkTokenRevocationStorage is an optional extension of TokenRevocationStorage for storages that revoke all tokens
+// of a subject or client at once.
+type BulkTokenRevocationStorage interface {
+	// RevokeTokens revokes all access tokens, refresh tokens and authorization codes matching the filter.
+	RevokeTokens(ctx context.Context, filter fosite.BulkRevocationFilter) error
+}
@@ -1,13 +1,17 @@
 package oauth2
 
 import (
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/internal"
+	"github.com/ory/fosite/storage"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRevokeToken(t *testing.T) {
@@ -16,6 +20,7 @@ func TestRevokeToken(t *testing.T) {
 	atStrat := internal.NewMockAccessTokenStrategy(ctrl)
 	rtStrat := internal.NewMockRefreshTokenStrategy(ctrl)
 	ar := internal.NewMockAccessRequester(ctrl)
+	client := &fosite.DefaultClient{ID: "foo"}
 	defer ctrl.Finish()
 
 	h := TokenRevocationHandler{
@@ -40,6 +45,7 @@ func TestRevokeToken(t *testing.T) {
 				tokenType = fosite.RefreshToken
 				rtStrat.EXPECT().RefreshTokenSignature(token)
 				store.EXPECT().GetRefreshTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(ar, nil)
+				ar.EXPECT().GetClient().AnyTimes().Return(client)
 				ar.EXPECT().GetID()
 				store.EXPECT().RevokeRefreshToken(gomock.Any(), gomock.Any())
 				store.EXPECT().RevokeAccessToken(gomock.Any(), gomock.Any())
@@ -53,6 +59,7 @@ func TestRevokeToken(t *testing.T) {
 				tokenType = fosite.AccessToken
 				atStrat.EXPECT().AccessTokenSignature(token)
 				store.EXPECT().GetAccessTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(ar, nil)
+				ar.EXPECT().GetClient().AnyTimes().Return(client)
 				ar.EXPECT().GetID()
 				store.EXPECT().RevokeRefreshToken(gomock.Any(), gomock.Any())
 				store.EXPECT().RevokeAccessToken(gomock.Any(), gomock.Any())
@@ -69,6 +76,7 @@ func TestRevokeToken(t *testing.T) {
 
 				rtStrat.EXPECT().RefreshTokenSignature(token)
 				store.EXPECT().GetRefreshTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(ar, nil)
+				ar.EXPECT().GetClient().AnyTimes().Return(client)
 				ar.EXPECT().GetID()
 				store.EXPECT().RevokeRefreshToken(gomock.Any(), gomock.Any())
 				store.EXPECT().RevokeAccessToken(gomock.Any(), gomock.Any())
@@ -85,6 +93,7 @@ func TestRevokeToken(t *testing.T) {
 
 				atStrat.EXPECT().AccessTokenSignature(token)
 				store.EXPECT().GetAccessTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(ar, nil)
+				ar.EXPECT().GetClient().AnyTimes().Return(client)
 				ar.EXPECT().GetID()
 				store.EXPECT().RevokeRefreshToken(gomock.Any(), gomock.Any())
 				store.EXPECT().RevokeAccessToken(gomock.Any(), gomock.Any())
@@ -116,10 +125,218 @@ func TestRevokeToken(t *testing.T) {
 				store.EXPECT().GetRefreshTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fosite.ErrNotFound)
 			},
 		},
+		{
+			description: "should fail - token was issued to another client",
+			expectErr:   fosite.ErrUnauthorizedClient,
+			mock: func() {
+				token = "foo"
+				tokenType = fosite.AccessToken
+				atStrat.EXPECT().AccessTokenSignature(token)
+				other := fosite.NewAccessRequest(nil)
+				other.Client = &fosite.DefaultClient{ID: "bar"}
+				store.EXPECT().GetAccessTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(other, nil)
+			},
+		},
+		{
+			description: "should fail - revoking the refresh token fails",
+			expectErr:   fosite.ErrServerError,
+			mock: func() {
+				token = "foo"
+				tokenType = fosite.RefreshToken
+				rtStrat.EXPECT().RefreshTokenSignature(token)
+				store.EXPECT().GetRefreshTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(ar, nil)
+				ar.EXPECT().GetClient().AnyTimes().Return(client)
+				ar.EXPECT().GetID()
+				store.EXPECT().RevokeRefreshToken(gomock.Any(), gomock.Any()).Return(errors.New("storage error"))
+			},
+		},
+		{
+			description: "should fail - authorization codes are not supported",
+			expectErr:   fosite.ErrUnsupportedTokenType,
+			mock: func() {
+				token = "foo"
+				tokenType = fosite.AuthorizeCode
+			},
+		},
+		{
+			description: "should fail - unknown token type hint",
+			expectErr:   fosite.ErrUnsupportedTokenType,
+			mock: func() {
+				token = "foo"
+				tokenType = fosite.TokenType("bar")
+			},
+		},
 	} {
 		c.mock()
-		err := h.RevokeToken(nil, token, tokenType)
+		err := h.RevokeToken(nil, token, tokenType, client)
 		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+type revocationCodeStorage struct {
+	*internal.MockTokenRevocationStorage
+	*internal.MockAuthorizeCodeStorage
+	*internal.MockOpenIDConnectRequestStorage
+}
+
+func TestRevokeAuthorizeCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := revocationCodeStorage{
+		MockTokenRevocationStorage:      internal.NewMockTokenRevocationStorage(ctrl),
+		MockAuthorizeCodeStorage:        internal.NewMockAuthorizeCodeStorage(ctrl),
+		MockOpenIDConnectRequestStorage: internal.NewMockOpenIDConnectRequestStorage(ctrl),
+	}
+	atStrat := internal.NewMockAccessTokenStrategy(ctrl)
+	rtStrat := internal.NewMockRefreshTokenStrategy(ctrl)
+	acStrat := internal.NewMockAuthorizeCodeStrategy(ctrl)
+	defer ctrl.Finish()
+
+	h := TokenRevocationHandler{
+		TokenRevocationStorage: store,
+		RefreshTokenStrategy:   rtStrat,
+		AccessTokenStrategy:    atStrat,
+		AuthorizeCodeStrategy:  acStrat,
+	}
+
+	ar := fosite.NewAuthorizeRequest()
+	ar.ID = "request-id"
+	ar.Client = &fosite.DefaultClient{ID: "foo"}
+
+	for k, c := range []struct {
+		description string
+		tokenType   fosite.TokenType
+		client      fosite.Client
+		mock        func()
+		expectErr   error
+	}{
+		{
+			description: "should revoke the code, its openid connect session and its tokens",
+			tokenType:   fosite.AuthorizeCode,
+			client:      &fosite.DefaultClient{ID: "foo"},
+			mock: func() {
+				acStrat.EXPECT().AuthorizeCodeSignature("code").AnyTimes().Return("code-sig")
+				store.MockAuthorizeCodeStorage.EXPECT().GetAuthorizeCodeSession(gomock.Any(), "code-sig", nil).Return(ar, nil)
+				store.MockAuthorizeCodeStorage.EXPECT().DeleteAuthorizeCodeSession(gomock.Any(), "code-sig").Return(nil)
+				store.MockOpenIDConnectRequestStorage.EXPECT().DeleteOpenIDConnectSession(gomock.Any(), "code").Return(fosite.ErrNotFound)
+				store.MockTokenRevocationStorage.EXPECT().RevokeRefreshToken(gomock.Any(), "request-id").Return(nil)
+				store.MockTokenRevocationStorage.EXPECT().RevokeAccessToken(gomock.Any(), "request-id").Return(nil)
+			},
+		},
+		{
+			description: "should find the code without a hint",
+			client:      &fosite.DefaultClient{ID: "foo"},
+			mock: func() {
+				rtStrat.EXPECT().RefreshTokenSignature("code")
+				store.MockTokenRevocationStorage.EXPECT().GetRefreshTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fosite.ErrNotFound)
+				atStrat.EXPECT().AccessTokenSignature("code")
+				store.MockTokenRevocationStorage.EXPECT().GetAccessTokenSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fosite.ErrNotFound)
+				store.MockAuthorizeCodeStorage.EXPECT().GetAuthorizeCodeSession(gomock.Any(), "code-sig", nil).Return(ar, nil)
+				store.MockAuthorizeCodeStorage.EXPECT().DeleteAuthorizeCodeSession(gomock.Any(), "code-sig").Return(nil)
+				store.MockOpenIDConnectRequestStorage.EXPECT().DeleteOpenIDConnectSession(gomock.Any(), "code").Return(nil)
+				store.MockTokenRevocationStorage.EXPECT().RevokeRefreshToken(gomock.Any(), "request-id").Return(nil)
+				store.MockTokenRevocationStorage.EXPECT().RevokeAccessToken(gomock.Any(), "request-id").Return(nil)
+			},
+		},
+		{
+			description: "should fail because the code was issued to another client",
+			tokenType:   fosite.AuthorizeCode,
+			client:      &fosite.DefaultClient{ID: "bar"},
+			mock: func() {
+				store.MockAuthorizeCodeStorage.EXPECT().GetAuthorizeCodeSession(gomock.Any(), "code-sig", nil).Return(ar, nil)
+			},
+			expectErr: fosite.ErrUnauthorizedClient,
+		},
+		{
+			description: "should fail because the code could not be deleted",
+			tokenType:   fosite.AuthorizeCode,
+			client:      &fosite.DefaultClient{ID: "foo"},
+			mock: func() {
+				store.MockAuthorizeCodeStorage.EXPECT().GetAuthorizeCodeSession(gomock.Any(), "code-sig", nil).Return(ar, nil)
+				store.MockAuthorizeCodeStorage.EXPECT().DeleteAuthorizeCodeSession(gomock.Any(), "code-sig").Return(errors.New("storage error"))
+			},
+			expectErr: fosite.ErrServerError,
+		},
+	} {
+		c.mock()
+		err := h.RevokeToken(nil, "code", c.tokenType, c.client)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestRevokeTokensInBulk(t *testing.T) {
+	newRequester := func(subject, clientID string) fosite.Requester {
+		r := fosite.NewAccessRequest(&fosite.DefaultSession{Subject: subject})
+		r.Client = &fosite.DefaultClient{ID: clientID}
+		return r
+	}
+
+	for k, c := range []struct {
+		description string
+		filter      fosite.BulkRevocationFilter
+		expectLeft  []string
+	}{
+		{
+			description: "should revoke all tokens of the subject",
+			filter:      fosite.BulkRevocationFilter{Subject: "peter"},
+			expectLeft:  []string{"alice-foo"},
+		},
+		{
+			description: "should revoke all tokens of the client",
+			filter:      fosite.BulkRevocationFilter{ClientID: "foo"},
+			expectLeft:  []string{"peter-bar"},
+		},
+		{
+			description: "should revoke all tokens of the subject and client",
+			filter:      fosite.BulkRevocationFilter{Subject: "peter", ClientID: "foo"},
+			expectLeft:  []string{"alice-foo", "peter-bar"},
+		},
+	} {
+		store := storage.NewMemoryStore()
+		for _, key := range []string{"peter-foo", "peter-bar", "alice-foo"} {
+			parts := strings.Split(key, "-")
+			require.Nil(t, store.CreateAccessTokenSession(nil, key, newRequester(parts[0], parts[1])))
+			require.Nil(t, store.CreateRefreshTokenSession(nil, key, newRequester(parts[0], parts[1])))
+			require.Nil(t, store.CreateAuthorizeCodeSession(nil, key, newRequester(parts[0], parts[1])))
+			require.Nil(t, store.CreateOpenIDConnectSession(nil, key, newRequester(parts[0], parts[1])))
+		}
+
+		h := TokenRevocationHandler{TokenRevocationStorage: store}
+		require.Nil(t, h.RevokeTokens(nil, c.filter), "(%d) %s", k, c.description)
+
+		for _, tokens := range []map[string]fosite.Requester{store.AccessTokens, store.RefreshTokens, store.AuthorizeCodes, store.IDSessions} {
+			var left []string
+			for key := range tokens {
+				left = append(left, key)
+			}
+			sort.Strings(left)
+			assert.Equal(t, c.expectLeft, left, "(%d) %s", k, c.description)
+		}
+	}
+
+	h := TokenRevocationHandler{TokenRevocationStorage: internal.NewMockTokenRevocationStorage(gomock.NewController(t))}
+	assert.Equal(t, fosite.ErrUnknownRequest, errors.Cause(h.RevokeTokens(nil, fosite.BulkRevocationFilter{Subject: "peter"})))
+}
+
+func TestRevokeTokensUnindexesDeletedTokens(t *testing.T) {
+	store := storage.NewMemoryStore()
+	r := fosite.NewAccessRequest(&fosite.DefaultSession{Subject: "peter"})
+	r.Client = &fosite.DefaultClient{ID: "foo"}
+
+	require.Nil(t, store.CreateAccessTokenSession(nil, "access", r))
+	require.Nil(t, store.CreateRefreshTokenSession(nil, "refresh", r))
+	require.Nil(t, store.CreateAuthorizeCodeSession(nil, "code", r))
+	require.Nil(t, store.CreateOpenIDConnectSession(nil, "code", r))
+	assert.Len(t, store.SubjectTokens["peter"], 4)
+	assert.Len(t, store.ClientTokens["foo"], 4)
+
+	require.Nil(t, store.RevokeAccessToken(nil, r.GetID()))
+	require.Nil(t, store.RevokeRefreshToken(nil, r.GetID()))
+	require.Nil(t, store.DeleteAuthorizeCodeSession(nil, "code"))
+	require.Nil(t, store.DeleteOpenIDConnectSession(nil, "code"))
+	assert.Empty(t, store.SubjectTokens)
+	assert.Empty(t, store.ClientTokens)
+	assert.Empty(t, store.AccessTokenRequestIDs)
+	assert.Empty(t, store.RefreshTokenRequestIDs)
+}
@@ -46,7 +46,11 @@ func (h HMACSHAStrategy) GenerateRefreshToken(_ context.Context, _ fosite.Reques
 	return h.Enigma.Generate()
 }
 
-func (h HMACSHAStrategy) ValidateRefreshToken(_ context.Context, _ fosite.Requester, token string) (err error) {
+func (h HMACSHAStrategy) ValidateRefreshToken(_ context.Context, r fosite.Requester, token string) (err error) {
+	var exp = r.GetSession().GetExpiresAt(fosite.RefreshToken)
+	if !exp.IsZero() && exp.Before(time.Now()) {
+		return errors.Wrap(fosite.ErrTokenExpired, fmt.Sprintf("Refresh token expired at %s", exp))
+	}
 	return h.Enigma.Validate(token)
 }
 
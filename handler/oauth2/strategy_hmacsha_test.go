@@ -22,6 +22,7 @@ var hmacExpiredCase = fosite.Request{
 		ExpiresAt: map[fosite.TokenType]time.Time{
 			fosite.AccessToken:   time.Now().Add(-time.Hour),
 			fosite.AuthorizeCode: time.Now().Add(-time.Hour),
+			fosite.RefreshToken:  time.Now().Add(-time.Hour),
 		},
 	},
 }
@@ -34,6 +35,7 @@ var hmacValidCase = fosite.Request{
 		ExpiresAt: map[fosite.TokenType]time.Time{
 			fosite.AccessToken:   time.Now().Add(time.Hour),
 			fosite.AuthorizeCode: time.Now().Add(time.Hour),
+			fosite.RefreshToken:  time.Now().Add(time.Hour),
 		},
 	},
 }
@@ -76,6 +78,9 @@ func TestHMACRefreshToken(t *testing.T) {
 	err = s.ValidateRefreshToken(nil, &hmacValidCase, token)
 	assert.Nil(t, err, "%s", err)
 	assert.Equal(t, signature, validate)
+
+	err = s.ValidateRefreshToken(nil, &hmacExpiredCase, token)
+	assert.NotNil(t, err)
 }
 
 func TestHMACAuthorizeCode(t *testing.T) {
@@ -16,8 +16,30 @@ import (
 type RS256JWTStrategy struct {
 	*jwt.RS256JWTStrategy
 	Issuer string
+
+	// RFC9068 enables the JWT profile for OAuth 2.0 access tokens defined in https://tools.ietf.org/html/rfc9068.
+	// Access tokens are then typed at+jwt and carry the client_id, a space-delimited scope and, if the session
+	// implements AuthenticationContextSession, auth_time and acr. Only at+jwt tokens are accepted as access tokens.
+	RFC9068 bool
+
+	// DefaultAudience is used as the aud claim of RFC 9068 access tokens that were not granted an audience. The aud
+	// claim is required, so generating such a token fails if DefaultAudience is empty.
+	DefaultAudience []string
+}
+
+// AuthenticationContextSession is an optional interface for sessions that know when and how the resource owner
+// authenticated, see https://tools.ietf.org/html/rfc9068#section-2.2.1
+type AuthenticationContextSession interface {
+	// GetAuthTime returns the time the resource owner authenticated, or the zero time if unknown.
+	GetAuthTime() time.Time
+
+	// GetACR returns the authentication context class reference, or an empty string if unknown.
+	GetACR() string
 }
 
+// rfc9068TokenTypes are the typ header values of RFC 9068 access tokens.
+var rfc9068TokenTypes = []string{"at+jwt", "application/at+jwt"}
+
 func (h RS256JWTStrategy) signature(token string) string {
 	split := strings.Split(token, ".")
 	if len(split) != 3 {
@@ -43,13 +65,21 @@ func (h *RS256JWTStrategy) ValidateJWT(tokenType fosite.TokenType, token string)
 	t, err := h.validate(token)
 	if err != nil {
 		return nil, err
+	} else if err := h.validateType(tokenType, t); err != nil {
+		return nil, err
 	}
 
 	claims := jwt.JWTClaims{}
 	claims.FromMapClaims(t.Claims.(jwtx.MapClaims))
 
+	// RFC 9068 access tokens carry a space-delimited scope claim instead of scp.
+	if scope, ok := claims.Extra["scope"].(string); ok && len(claims.Scope) == 0 {
+		claims.Scope = fosite.Arguments(strings.Split(scope, " "))
+	}
+	clientID, _ := claims.Extra["client_id"].(string)
+
 	requester = &fosite.Request{
-		Client:      &fosite.DefaultClient{},
+		Client:      &fosite.DefaultClient{ID: clientID},
 		RequestedAt: claims.IssuedAt,
 		Session: &JWTSession{
 			JWTClaims: &claims,
@@ -61,20 +91,54 @@ func (h *RS256JWTStrategy) ValidateJWT(tokenType fosite.TokenType, token string)
 			},
 			Subject: claims.Subject,
 		},
-		Scopes:        claims.Scope,
-		GrantedScopes: claims.Scope,
+		Scopes:            claims.Scope,
+		GrantedScopes:     claims.Scope,
+		RequestedAudience: claims.Audience,
+		GrantedAudience:   claims.Audience,
+
+		RequestedAuthorizationDetails: authorizationDetailsFromClaim(claims.Extra["authorization_details"]),
+		GrantedAuthorizationDetails:   authorizationDetailsFromClaim(claims.Extra["authorization_details"]),
+
+		DPoPJWKThumbprint: dpopJWKThumbprintFromClaim(claims.Extra["cnf"]),
 	}
 
 	return
 }
 
+// dpopJWKThumbprintFromClaim returns the DPoP key thumbprint of the cnf claim of a decoded token, see
+// https://tools.ietf.org/html/rfc9449#section-6.1
+func dpopJWKThumbprintFromClaim(claim interface{}) string {
+	switch v := claim.(type) {
+	case map[string]interface{}:
+		thumbprint, _ := v["jkt"].(string)
+		return thumbprint
+	}
+	return ""
+}
+
+// authorizationDetailsFromClaim converts the authorization_details claim of a decoded token back into
+// authorization details.
+func authorizationDetailsFromClaim(claim interface{}) fosite.AuthorizationDetails {
+	details := fosite.AuthorizationDetails{}
+	switch v := claim.(type) {
+	case fosite.AuthorizationDetails:
+		return v
+	case []interface{}:
+		for _, detail := range v {
+			if d, ok := detail.(map[string]interface{}); ok {
+				details = append(details, d)
+			}
+		}
+	}
+	return details
+}
+
 func (h *RS256JWTStrategy) GenerateAccessToken(_ context.Context, requester fosite.Requester) (token string, signature string, err error) {
 	return h.generate(fosite.AccessToken, requester)
 }
 
 func (h *RS256JWTStrategy) ValidateAccessToken(_ context.Context, _ fosite.Requester, token string) error {
-	_, err := h.validate(token)
-	return err
+	return h.validateWithType(fosite.AccessToken, token)
 }
 
 func (h *RS256JWTStrategy) GenerateRefreshToken(_ context.Context, requester fosite.Requester) (token string, signature string, err error) {
@@ -82,8 +146,7 @@ func (h *RS256JWTStrategy) GenerateRefreshToken(_ context.Context, requester fos
 }
 
 func (h *RS256JWTStrategy) ValidateRefreshToken(_ context.Context, _ fosite.Requester, token string) error {
-	_, err := h.validate(token)
-	return err
+	return h.validateWithType(fosite.RefreshToken, token)
 }
 
 func (h *RS256JWTStrategy) GenerateAuthorizeCode(_ context.Context, requester fosite.Requester) (token string, signature string, err error) {
@@ -91,8 +154,38 @@ func (h *RS256JWTStrategy) GenerateAuthorizeCode(_ context.Context, requester fo
 }
 
 func (h *RS256JWTStrategy) ValidateAuthorizeCode(_ context.Context, requester fosite.Requester, token string) error {
-	_, err := h.validate(token)
-	return err
+	return h.validateWithType(fosite.AuthorizeCode, token)
+}
+
+func (h *RS256JWTStrategy) validateWithType(tokenType fosite.TokenType, token string) error {
+	t, err := h.validate(token)
+	if err != nil {
+		return err
+	}
+	return h.validateType(tokenType, t)
+}
+
+// validateType ensures that, if the RFC 9068 profile is enabled, only at+jwt tokens are accepted as access tokens
+// and at+jwt tokens are not accepted as any other type of token.
+func (h *RS256JWTStrategy) validateType(tokenType fosite.TokenType, t *jwtx.Token) error {
+	if !h.RFC9068 {
+		return nil
+	}
+
+	typ, _ := t.Header["typ"].(string)
+	isAccessToken := false
+	for _, accessTokenType := range rfc9068TokenTypes {
+		if strings.EqualFold(typ, accessTokenType) {
+			isAccessToken = true
+		}
+	}
+
+	if tokenType == fosite.AccessToken && !isAccessToken {
+		return errors.Wrapf(fosite.ErrInvalidTokenFormat, "Access tokens must be of type at+jwt but got %s", typ)
+	} else if tokenType != fosite.AccessToken && isAccessToken {
+		return errors.Wrapf(fosite.ErrInvalidTokenFormat, "Access tokens must not be used as %s", tokenType)
+	}
+	return nil
 }
 
 func (h *RS256JWTStrategy) validate(token string) (t *jwtx.Token, err error) {
@@ -152,7 +245,56 @@ func (h *RS256JWTStrategy) generate(tokenType fosite.TokenType, requester fosite
 		}
 
 		claims.Scope = requester.GetGrantedScopes()
+		claims.Audience = requester.GetGrantedAudience()
+		if details := requester.GetGrantedAuthorizationDetails(); len(details) > 0 {
+			claims.Add("authorization_details", details)
+		} else {
+			delete(claims.Extra, "authorization_details")
+		}
+		if thumbprint := fosite.GetDPoPJWKThumbprint(requester); thumbprint != "" {
+			claims.Add("cnf", map[string]interface{}{"jkt": thumbprint})
+		} else {
+			delete(claims.Extra, "cnf")
+		}
+
+		if h.RFC9068 && tokenType == fosite.AccessToken {
+			return h.generateRFC9068(jwtSession, requester)
+		}
 
 		return h.RS256JWTStrategy.Generate(claims.ToMapClaims(), jwtSession.GetJWTHeader())
 	}
 }
+
+// generateRFC9068 generates an access token following https://tools.ietf.org/html/rfc9068#section-2 from the
+// session's claims and header without modifying them.
+func (h *RS256JWTStrategy) generateRFC9068(session JWTSessionContainer, requester fosite.Requester) (string, string, error) {
+	claims := session.GetJWTClaims().ToMapClaims()
+	delete(claims, "scp")
+	if scopes := requester.GetGrantedScopes(); len(scopes) > 0 {
+		claims["scope"] = strings.Join(scopes, " ")
+	}
+	claims["client_id"] = requester.GetClient().GetID()
+	if _, ok := claims["aud"]; !ok {
+		if len(h.DefaultAudience) == 0 {
+			return "", "", errors.Wrap(fosite.ErrServerError, "The access token was not granted an audience and no default audience is configured")
+		}
+		claims["aud"] = h.DefaultAudience
+	}
+
+	if s, ok := session.(AuthenticationContextSession); ok {
+		if authTime := s.GetAuthTime(); !authTime.IsZero() {
+			claims["auth_time"] = float64(authTime.Unix()) // jwt-go does not support int64 as datatype
+		}
+		if acr := s.GetACR(); acr != "" {
+			claims["acr"] = acr
+		}
+	}
+
+	header := &jwt.Headers{}
+	for k, v := range session.GetJWTHeader().Extra {
+		header.Add(k, v)
+	}
+	header.Add("typ", "at+jwt")
+
+	return h.RS256JWTStrategy.Generate(claims, header)
+}
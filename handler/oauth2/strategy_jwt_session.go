@@ -26,6 +26,11 @@ type JWTSession struct {
 	ExpiresAt map[fosite.TokenType]time.Time
 	Username  string
 	Subject   string
+
+	// AuthTime and ACR describe when and how the resource owner authenticated. They are added to access tokens
+	// following the RFC 9068 profile.
+	AuthTime time.Time
+	ACR      string
 }
 
 func (j *JWTSession) GetJWTClaims() *jwt.JWTClaims {
@@ -75,6 +80,28 @@ func (s *JWTSession) GetSubject() string {
 	return s.Subject
 }
 
+func (s *JWTSession) GetAuthTime() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	return s.AuthTime
+}
+
+func (s *JWTSession) GetACR() string {
+	if s == nil {
+		return ""
+	}
+	return s.ACR
+}
+
+// GetTokenID returns the jti claim of the token.
+func (s *JWTSession) GetTokenID() string {
+	if s == nil || s.JWTClaims == nil {
+		return ""
+	}
+	return s.JWTClaims.JTI
+}
+
 func (s *JWTSession) Clone() fosite.Session {
 	if s == nil {
 		return nil
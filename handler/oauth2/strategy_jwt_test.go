@@ -5,10 +5,13 @@ import (
 	"testing"
 	"time"
 
+	jwtx "github.com/dgrijalva/jwt-go"
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/internal"
 	"github.com/ory/fosite/token/jwt"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var j = &RS256JWTStrategy{
@@ -29,7 +32,7 @@ var jwtValidCase = func(tokenType fosite.TokenType) *fosite.Request {
 			JWTClaims: &jwt.JWTClaims{
 				Issuer:    "fosite",
 				Subject:   "peter",
-				Audience:  "group0",
+				Audience:  []string{"group0"},
 				IssuedAt:  time.Now(),
 				NotBefore: time.Now(),
 				Extra:     make(map[string]interface{}),
@@ -56,7 +59,7 @@ var jwtExpiredCase = func(tokenType fosite.TokenType) *fosite.Request {
 			JWTClaims: &jwt.JWTClaims{
 				Issuer:    "fosite",
 				Subject:   "peter",
-				Audience:  "group0",
+				Audience:  []string{"group0"},
 				IssuedAt:  time.Now(),
 				NotBefore: time.Now(),
 				Extra:     make(map[string]interface{}),
@@ -100,6 +103,26 @@ func TestAccessToken(t *testing.T) {
 	}
 }
 
+func TestAccessTokenWithAuthorizationDetails(t *testing.T) {
+	r := jwtValidCase(fosite.AccessToken)
+	r.GrantAuthorizationDetail(fosite.AuthorizationDetail{"type": "payment_initiation", "instructedAmount": "45.00"})
+
+	token, _, err := j.GenerateAccessToken(nil, r)
+	assert.Nil(t, err, "%s", err)
+
+	requester, err := j.ValidateJWT(fosite.AccessToken, token)
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, r.GetGrantedAuthorizationDetails(), requester.GetGrantedAuthorizationDetails())
+
+	r.GrantedAuthorizationDetails = nil
+	token, _, err = j.GenerateAccessToken(nil, r)
+	assert.Nil(t, err, "%s", err)
+
+	requester, err = j.ValidateJWT(fosite.AccessToken, token)
+	assert.Nil(t, err, "%s", err)
+	assert.Empty(t, requester.GetGrantedAuthorizationDetails())
+}
+
 func TestRefreshToken(t *testing.T) {
 	token, signature, err := j.GenerateRefreshToken(nil, jwtValidCase(fosite.RefreshToken))
 	assert.Nil(t, err, "%s", err)
@@ -139,3 +162,65 @@ func TestGenerateAuthorizeCode(t *testing.T) {
 		}
 	}
 }
+
+func TestAccessTokenRFC9068(t *testing.T) {
+	profile := &RS256JWTStrategy{
+		RS256JWTStrategy: j.RS256JWTStrategy,
+		RFC9068:          true,
+		DefaultAudience:  []string{"https://api.example.com"},
+	}
+
+	r := jwtValidCase(fosite.AccessToken)
+	r.Client = &fosite.DefaultClient{ID: "foo"}
+	r.GrantScope("photos")
+	r.GrantScope("offline")
+	r.GrantAudience("https://photos.example.com")
+	authTime := time.Now().Add(-time.Minute)
+	r.Session.(*JWTSession).AuthTime = authTime
+	r.Session.(*JWTSession).ACR = "urn:mace:incommon:iap:silver"
+
+	token, _, err := profile.GenerateAccessToken(nil, r)
+	require.Nil(t, err, "%s", err)
+
+	parsed, err := profile.RS256JWTStrategy.Decode(token)
+	require.Nil(t, err, "%s", err)
+	claims := parsed.Claims.(jwtx.MapClaims)
+	assert.Equal(t, "at+jwt", parsed.Header["typ"])
+	assert.Equal(t, "foo", claims["client_id"])
+	assert.Equal(t, "photos offline", claims["scope"])
+	assert.Equal(t, []interface{}{"https://photos.example.com"}, claims["aud"])
+	assert.Equal(t, float64(authTime.Unix()), claims["auth_time"])
+	assert.Equal(t, "urn:mace:incommon:iap:silver", claims["acr"])
+	assert.NotEmpty(t, claims["jti"])
+	assert.Nil(t, claims["scp"])
+
+	assert.Nil(t, profile.ValidateAccessToken(nil, r, token))
+	assert.Equal(t, fosite.ErrInvalidTokenFormat, errors.Cause(profile.ValidateRefreshToken(nil, r, token)))
+
+	requester, err := profile.ValidateJWT(fosite.AccessToken, token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "foo", requester.GetClient().GetID())
+	assert.Equal(t, fosite.Arguments{"photos", "offline"}, requester.GetGrantedScopes())
+
+	// The session's claims and header are not modified by the profile.
+	refresh, _, err := profile.GenerateRefreshToken(nil, jwtValidCase(fosite.RefreshToken))
+	require.Nil(t, err, "%s", err)
+	assert.Nil(t, profile.ValidateRefreshToken(nil, r, refresh))
+	assert.Equal(t, fosite.ErrInvalidTokenFormat, errors.Cause(profile.ValidateAccessToken(nil, r, refresh)))
+	_, err = profile.ValidateJWT(fosite.AccessToken, refresh)
+	assert.Equal(t, fosite.ErrInvalidTokenFormat, errors.Cause(err))
+
+	r = jwtValidCase(fosite.AccessToken)
+	token, _, err = profile.GenerateAccessToken(nil, r)
+	require.Nil(t, err, "%s", err)
+	parsed, err = profile.RS256JWTStrategy.Decode(token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, []interface{}{"https://api.example.com"}, parsed.Claims.(jwtx.MapClaims)["aud"])
+	_, ok := r.Session.(*JWTSession).JWTHeader.Extra["typ"]
+	assert.False(t, ok)
+
+	// RFC 9068 requires the aud claim, so tokens without an audience can not be generated without a default.
+	profile.DefaultAudience = nil
+	_, _, err = profile.GenerateAccessToken(nil, jwtValidCase(fosite.AccessToken))
+	assert.Equal(t, fosite.ErrServerError, errors.Cause(err))
+}
@@ -0,0 +1,265 @@
+package openid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	"github.com/ory/fosite"
+	"github.com/pkg/errors"
+)
+
+// LogoutRequest is a validated RP-Initiated Logout request as defined in
+// https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+type LogoutRequest struct {
+	// Client is the relying party that initiated the logout, or nil if neither client_id nor id_token_hint were
+	// sent.
+	Client fosite.Client
+
+	// Subject and SessionID are the "sub" and "sid" claims of the id_token_hint, if sent. If the client uses
+	// pairwise subject identifiers, Subject is the pairwise identifier and not the end-user's local account id, see
+	// LogoutHandler.SubjectResolver.
+	Subject   string
+	SessionID string
+
+	// PostLogoutRedirectURI is the validated post_logout_redirect_uri, or nil if none was sent.
+	PostLogoutRedirectURI *url.URL
+
+	// State is passed back to the relying party when redirecting to the post logout redirect URI.
+	State string
+
+	// Form contains all parameters of the request, e.g. logout_hint and ui_locales.
+	Form url.Values
+}
+
+// LogoutHook is called by LogoutHandler.Logout so that the host application can end the user's login session.
+type LogoutHook func(ctx context.Context, request *LogoutRequest) error
+
+// LogoutSubjectResolver returns the end-user's local account id for a logout request of a client using pairwise
+// subject identifiers, e.g. by looking up the login session identified by SessionID.
+type LogoutSubjectResolver func(ctx context.Context, request *LogoutRequest) (subject string, err error)
+
+// TokenRevoker revokes the tokens of a subject for a client, e.g. fosite.OAuth2Provider.
+type TokenRevoker interface {
+	RevokeTokensBySubjectAndClient(ctx context.Context, subject, clientID string) error
+}
+
+// LogoutHandler implements the end_session endpoint of OpenID Connect RP-Initiated Logout, see
+// https://openid.net/specs/openid-connect-rpinitiated-1_0.html
+type LogoutHandler struct {
+	// IDTokenStrategy is the strategy that issued the ID tokens. It is used to verify the id_token_hint.
+	IDTokenStrategy *DefaultStrategy
+
+	// Store is used to look up the relying party.
+	Store fosite.Storage
+
+	// Hook ends the user's login session in the host application.
+	Hook LogoutHook
+
+	// TokenRevoker, if set, revokes all tokens the user granted to the relying party on logout.
+	TokenRevoker TokenRevoker
+
+	// SubjectResolver resolves the local account id if the relying party uses pairwise subject identifiers. It is
+	// required to revoke the tokens of such relying parties.
+	SubjectResolver LogoutSubjectResolver
+}
+
+// NewLogoutRequest parses and validates a logout request as defined in
+// https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+//
+//   This specification defines the following parameters that are used in
+//   the logout request at the Logout Endpoint:
+//
+//   id_token_hint
+//     RECOMMENDED. ID Token previously issued by the OP to the RP passed
+//     to the Logout Endpoint as a hint about the End-User's current
+//     authenticated session with the Client.
+//
+//   post_logout_redirect_uri
+//     OPTIONAL. URI to which the RP is requesting that the End-User's
+//     User Agent be redirected after a logout has been performed. This URI
+//     SHOULD use the https scheme [...]. The value MUST have been
+//     previously registered with the OP.
+//
+//   state
+//     OPTIONAL. Opaque value used by the RP to maintain state between the
+//     logout request and the callback to the endpoint specified by the
+//     post_logout_redirect_uri parameter.
+//
+// Expired ID tokens are accepted as id_token_hint, because the RP may log the user out long after the ID token was
+// issued.
+func (h *LogoutHandler) NewLogoutRequest(ctx context.Context, r *http.Request) (*LogoutRequest, error) {
+	if r.Method != "GET" && r.Method != "POST" {
+		return nil, errors.Wrap(fosite.ErrInvalidRequest, "HTTP method is not GET or POST")
+	} else if err := r.ParseForm(); err != nil {
+		return nil, errors.Wrap(fosite.ErrInvalidRequest, err.Error())
+	}
+
+	lr := &LogoutRequest{
+		State: r.Form.Get("state"),
+		Form:  r.Form,
+	}
+
+	clientID := r.Form.Get("client_id")
+	if hint := r.Form.Get("id_token_hint"); hint != "" {
+		claims, err := h.decodeIDTokenHint(hint)
+		if err != nil {
+			return nil, err
+		}
+
+		audience := audienceFromClaim(claims["aud"])
+		if clientID == "" && len(audience) > 0 {
+			clientID = audience[0]
+		} else if clientID != "" && !fosite.ExactAudienceMatchingStrategy(audience, clientID) {
+			return nil, errors.Wrap(fosite.ErrInvalidRequest, "Parameter client_id does not match the audience of id_token_hint")
+		}
+
+		lr.Subject, _ = claims["sub"].(string)
+		lr.SessionID, _ = claims["sid"].(string)
+	}
+
+	if clientID != "" {
+		client, err := h.Store.GetClient(ctx, clientID)
+		if err != nil {
+			return nil, errors.Wrap(fosite.ErrInvalidClient, err.Error())
+		}
+		lr.Client = client
+	}
+
+	if raw := r.Form.Get("post_logout_redirect_uri"); raw != "" {
+		if lr.Client == nil {
+			return nil, errors.Wrap(fosite.ErrInvalidRequest, "Parameter post_logout_redirect_uri requires client_id or id_token_hint")
+		}
+
+		pc, ok := lr.Client.(fosite.ClientWithPostLogoutRedirectURIs)
+		if !ok || !fosite.ExactAudienceMatchingStrategy(pc.GetPostLogoutRedirectURIs(), raw) {
+			return nil, errors.Wrap(fosite.ErrInvalidRequest, "Parameter post_logout_redirect_uri is not registered for this client")
+		}
+
+		redirectURI, err := url.Parse(raw)
+		if err != nil {
+			return nil, errors.Wrap(fosite.ErrInvalidRequest, err.Error())
+		}
+		lr.PostLogoutRedirectURI = redirectURI
+	}
+
+	return lr, nil
+}
+
+// Logout calls the logout hook and, if a TokenRevoker is configured, revokes all tokens the user granted to the
+// relying party. Hosts usually call it after the user confirmed the logout.
+//
+// The subject of relying parties using pairwise subject identifiers is resolved using SubjectResolver before the hook
+// ends the login session. Logout fails if it can not be resolved, instead of revoking no tokens.
+func (h *LogoutHandler) Logout(ctx context.Context, lr *LogoutRequest) error {
+	var subject string
+	if h.TokenRevoker != nil && lr.Subject != "" && lr.Client != nil {
+		var err error
+		if subject, err = h.resolveSubject(ctx, lr); err != nil {
+			return err
+		}
+	}
+
+	if h.Hook != nil {
+		if err := h.Hook(ctx, lr); err != nil {
+			return err
+		}
+	}
+
+	if subject != "" {
+		if err := h.TokenRevoker.RevokeTokensBySubjectAndClient(ctx, subject, lr.Client.GetID()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveSubject returns the local account id of the end-user logging out.
+func (h *LogoutHandler) resolveSubject(ctx context.Context, lr *LogoutRequest) (string, error) {
+	if fosite.GetClientSubjectType(lr.Client) != fosite.SubjectTypePairwise {
+		return lr.Subject, nil
+	} else if h.SubjectResolver == nil {
+		return "", errors.Wrap(fosite.ErrServerError, "A subject resolver is required to revoke the tokens of clients using pairwise subject identifiers")
+	}
+
+	subject, err := h.SubjectResolver(ctx, lr)
+	if err != nil {
+		return "", err
+	} else if subject == "" {
+		return "", errors.Wrap(fosite.ErrServerError, "The pairwise subject identifier could not be resolved")
+	}
+	return subject, nil
+}
+
+// WriteLogoutResponse redirects the user agent to the post logout redirect URI, passing back the state. If the
+// request has no post logout redirect URI, nothing is written and the host should render its own logged out page.
+func (h *LogoutHandler) WriteLogoutResponse(rw http.ResponseWriter, lr *LogoutRequest) {
+	if lr.PostLogoutRedirectURI == nil {
+		return
+	}
+
+	redirectURI := *lr.PostLogoutRedirectURI
+	if lr.State != "" {
+		query := redirectURI.Query()
+		query.Set("state", lr.State)
+		redirectURI.RawQuery = query.Encode()
+	}
+
+	rw.Header().Set("Location", redirectURI.String())
+	rw.WriteHeader(http.StatusFound)
+}
+
+// WriteLogoutError writes an error response. Errors are never sent to the post logout redirect URI, because it could
+// not be validated.
+func (h *LogoutHandler) WriteLogoutError(rw http.ResponseWriter, err error) {
+	rw.Header().Set("Content-Type", "application/json;charset=UTF-8")
+
+	rfcerr := fosite.ErrorToRFC6749Error(err)
+	js, err := json.Marshal(rfcerr)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(rfcerr.Code)
+	rw.Write(js)
+}
+
+// decodeIDTokenHint verifies the signature and issuer of an ID token issued by IDTokenStrategy without validating
+// its expiry.
+func (h *LogoutHandler) decodeIDTokenHint(hint string) (jwtx.MapClaims, error) {
+	claims := jwtx.MapClaims{}
+	parser := &jwtx.Parser{ValidMethods: []string{jwtx.SigningMethodRS256.Alg()}, SkipClaimsValidation: true}
+	if _, err := parser.ParseWithClaims(hint, claims, func(*jwtx.Token) (interface{}, error) {
+		return &h.IDTokenStrategy.PrivateKey.PublicKey, nil
+	}); err != nil {
+		return nil, errors.Wrapf(fosite.ErrInvalidRequest, "Parameter id_token_hint is invalid: %s", err)
+	}
+
+	if h.IDTokenStrategy.Issuer != "" && !claims.VerifyIssuer(h.IDTokenStrategy.Issuer, true) {
+		return nil, errors.Wrap(fosite.ErrInvalidRequest, "Parameter id_token_hint was issued by another issuer")
+	}
+
+	return claims, nil
+}
+
+// audienceFromClaim returns the audience of a JWT, which is either a string or an array of strings.
+func audienceFromClaim(aud interface{}) []string {
+	switch v := aud.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var audience []string
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				audience = append(audience, s)
+			}
+		}
+		return audience
+	}
+	return nil
+}
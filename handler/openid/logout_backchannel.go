@@ -0,0 +1,256 @@
+package openid
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+)
+
+// BackChannelLogoutEvent is the member of the events claim identifying a logout token, see
+// https://openid.net/specs/openid-connect-backchannel-1_0.html#LogoutToken
+const BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+const (
+	defaultLogoutTokenLifespan       = time.Minute * 2
+	defaultBackChannelMaxAttempts    = 5
+	defaultBackChannelRetryInterval  = time.Minute
+	defaultBackChannelRequestTimeout = time.Second * 10
+)
+
+// BackChannelLogoutSender delivers a logout token to a client's back-channel logout URI.
+type BackChannelLogoutSender interface {
+	Send(ctx context.Context, uri string, logoutToken string) error
+}
+
+// DefaultBackChannelLogoutSender POSTs logout tokens as defined in
+// https://openid.net/specs/openid-connect-backchannel-1_0.html#BCRequest
+type DefaultBackChannelLogoutSender struct {
+	// Client is the HTTP client used to send logout tokens. Defaults to a client with a ten second timeout.
+	Client *http.Client
+}
+
+func (s *DefaultBackChannelLogoutSender) Send(ctx context.Context, uri string, logoutToken string) error {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultBackChannelRequestTimeout}
+	}
+
+	req, err := http.NewRequest("POST", uri, strings.NewReader(url.Values{"logout_token": {logoutToken}}.Encode()))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	//   If the logout succeeded, the RP MUST respond with HTTP 200 OK.
+	//   However, note that some Web frameworks will substitute an HTTP 204
+	//   No Content response for an HTTP 200 OK when the HTTP body is empty.
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return errors.Errorf("Back-channel logout endpoint %s responded with status code %d", uri, res.StatusCode)
+	}
+	return nil
+}
+
+// BackChannelLogoutDelivery is a logout notification whose delivery failed and is retried later.
+type BackChannelLogoutDelivery struct {
+	ID            string
+	ClientID      string
+	URI           string
+	Subject       string
+	SessionID     string
+	Attempts      int
+	NextAttemptAt time.Time
+}
+
+// BackChannelLogoutStorage is the retry queue of back-channel logout notifications.
+type BackChannelLogoutStorage interface {
+	// SetBackChannelLogoutDelivery creates or updates a pending delivery.
+	SetBackChannelLogoutDelivery(ctx context.Context, delivery *BackChannelLogoutDelivery) error
+
+	// GetPendingBackChannelLogoutDeliveries returns the deliveries whose next attempt is due at the given time.
+	GetPendingBackChannelLogoutDeliveries(ctx context.Context, now time.Time) ([]*BackChannelLogoutDelivery, error)
+
+	// DeleteBackChannelLogoutDelivery removes a delivery from the queue.
+	DeleteBackChannelLogoutDelivery(ctx context.Context, id string) error
+}
+
+// BackChannelLogoutHandler notifies clients of logouts as defined in
+// https://openid.net/specs/openid-connect-backchannel-1_0.html
+type BackChannelLogoutHandler struct {
+	// IDTokenStrategy signs the logout tokens. Its issuer is used as the iss claim.
+	IDTokenStrategy *DefaultStrategy
+
+	// Sender delivers the logout tokens. Defaults to DefaultBackChannelLogoutSender.
+	Sender BackChannelLogoutSender
+
+	// Storage queues failed deliveries for RetryPending. If nil, failed deliveries are only reported as an error.
+	Storage BackChannelLogoutStorage
+
+	// LogoutTokenLifespan defaults to two minutes.
+	LogoutTokenLifespan time.Duration
+
+	// MaxAttempts is the number of delivery attempts after which a notification is dropped. Defaults to five.
+	MaxAttempts int
+
+	// RetryInterval is the delay before the first retry, doubled after every failed attempt. Defaults to one
+	// minute.
+	RetryInterval time.Duration
+}
+
+// GenerateLogoutToken returns a logout token for the client as defined in
+// https://openid.net/specs/openid-connect-backchannel-1_0.html#LogoutToken
+//
+//   A Logout Token MUST contain either a sub or a sid Claim, and MAY
+//   contain both.  If a sid Claim is not present, the intent is that all
+//   sessions at the RP for the End-User identified by the iss and sub
+//   Claims be logged out.
+func (h *BackChannelLogoutHandler) GenerateLogoutToken(clientID, subject, sessionID string) (string, error) {
+	if subject == "" && sessionID == "" {
+		return "", errors.New("Logout token requires a subject or a session id")
+	}
+
+	lifespan := h.LogoutTokenLifespan
+	if lifespan == 0 {
+		lifespan = defaultLogoutTokenLifespan
+	}
+
+	now := time.Now()
+	claims := jwtx.MapClaims{
+		"iss":    h.IDTokenStrategy.Issuer,
+		"aud":    clientID,
+		"iat":    float64(now.Unix()),
+		"exp":    float64(now.Add(lifespan).Unix()),
+		"jti":    uuid.New(),
+		"events": map[string]interface{}{BackChannelLogoutEvent: map[string]interface{}{}},
+	}
+	if subject != "" {
+		claims["sub"] = subject
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
+	}
+
+	token, _, err := h.IDTokenStrategy.RS256JWTStrategy.Generate(claims, &jwt.Headers{Extra: map[string]interface{}{"typ": "logout+jwt"}})
+	return token, err
+}
+
+// Logout notifies all clients that registered a back-channel logout URI that the user's session ended. Clients
+// requiring a session id are skipped if the session id is unknown. Failed deliveries are queued for RetryPending if
+// Storage is set, otherwise the first error is returned after all clients were notified.
+func (h *BackChannelLogoutHandler) Logout(ctx context.Context, clients []fosite.Client, subject, sessionID string) error {
+	var firstErr error
+	for _, client := range clients {
+		bc, ok := client.(fosite.ClientWithBackChannelLogout)
+		if !ok || bc.GetBackChannelLogoutURI() == "" {
+			continue
+		} else if bc.IsBackChannelLogoutSessionRequired() && sessionID == "" {
+			continue
+		}
+
+		// The logout token must carry the subject identifier the client saw in its ID tokens.
+		clientSubject, err := h.IDTokenStrategy.GetSubjectIdentifier(ctx, client, subject)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		delivery := &BackChannelLogoutDelivery{
+			ID:        uuid.New(),
+			ClientID:  client.GetID(),
+			URI:       bc.GetBackChannelLogoutURI(),
+			Subject:   clientSubject,
+			SessionID: sessionID,
+		}
+		if err := h.deliver(ctx, delivery); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// RetryPending retries the queued deliveries that are due. Deliveries are dropped after MaxAttempts attempts.
+func (h *BackChannelLogoutHandler) RetryPending(ctx context.Context) error {
+	if h.Storage == nil {
+		return nil
+	}
+
+	deliveries, err := h.Storage.GetPendingBackChannelLogoutDeliveries(ctx, time.Now())
+	if err != nil {
+		return errors.Wrap(fosite.ErrServerError, err.Error())
+	}
+
+	for _, delivery := range deliveries {
+		if err := h.deliver(ctx, delivery); err != nil && errors.Cause(err) == fosite.ErrServerError {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver sends a logout token and updates the retry queue. Delivery errors are only returned if there is no queue,
+// storage errors are always returned wrapped in fosite.ErrServerError.
+func (h *BackChannelLogoutHandler) deliver(ctx context.Context, delivery *BackChannelLogoutDelivery) error {
+	token, err := h.GenerateLogoutToken(delivery.ClientID, delivery.Subject, delivery.SessionID)
+	if err != nil {
+		return errors.Wrap(fosite.ErrServerError, err.Error())
+	}
+
+	sendErr := h.getSender().Send(ctx, delivery.URI, token)
+	if h.Storage == nil {
+		return sendErr
+	}
+
+	delivery.Attempts++
+	if sendErr == nil || delivery.Attempts >= h.getMaxAttempts() {
+		if delivery.Attempts == 1 && sendErr == nil {
+			// Never queued.
+			return nil
+		}
+		if err := h.Storage.DeleteBackChannelLogoutDelivery(ctx, delivery.ID); err != nil && errors.Cause(err) != fosite.ErrNotFound {
+			return errors.Wrap(fosite.ErrServerError, err.Error())
+		}
+		return nil
+	}
+
+	interval := h.RetryInterval
+	if interval == 0 {
+		interval = defaultBackChannelRetryInterval
+	}
+	delivery.NextAttemptAt = time.Now().Add(interval * time.Duration(1<<uint(delivery.Attempts-1)))
+	if err := h.Storage.SetBackChannelLogoutDelivery(ctx, delivery); err != nil {
+		return errors.Wrap(fosite.ErrServerError, err.Error())
+	}
+	return nil
+}
+
+func (h *BackChannelLogoutHandler) getSender() BackChannelLogoutSender {
+	if h.Sender == nil {
+		return &DefaultBackChannelLogoutSender{}
+	}
+	return h.Sender
+}
+
+func (h *BackChannelLogoutHandler) getMaxAttempts() int {
+	if h.MaxAttempts == 0 {
+		return defaultBackChannelMaxAttempts
+	}
+	return h.MaxAttempts
+}
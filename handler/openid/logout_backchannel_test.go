@@ -0,0 +1,145 @@
+package openid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	"github.com/ory/fosite"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type backChannelTestSender struct {
+	sent map[string][]string
+	fail map[string]bool
+}
+
+func (s *backChannelTestSender) Send(_ context.Context, uri string, logoutToken string) error {
+	if s.fail[uri] {
+		return errors.New("unreachable")
+	}
+	s.sent[uri] = append(s.sent[uri], logoutToken)
+	return nil
+}
+
+type backChannelTestStorage map[string]*BackChannelLogoutDelivery
+
+func (s backChannelTestStorage) SetBackChannelLogoutDelivery(_ context.Context, delivery *BackChannelLogoutDelivery) error {
+	d := *delivery
+	s[delivery.ID] = &d
+	return nil
+}
+
+func (s backChannelTestStorage) GetPendingBackChannelLogoutDeliveries(_ context.Context, now time.Time) ([]*BackChannelLogoutDelivery, error) {
+	var pending []*BackChannelLogoutDelivery
+	for _, d := range s {
+		if !d.NextAttemptAt.After(now) {
+			c := *d
+			pending = append(pending, &c)
+		}
+	}
+	return pending, nil
+}
+
+func (s backChannelTestStorage) DeleteBackChannelLogoutDelivery(_ context.Context, id string) error {
+	delete(s, id)
+	return nil
+}
+
+func TestBackChannelLogoutHandler_GenerateLogoutToken(t *testing.T) {
+	h := &BackChannelLogoutHandler{IDTokenStrategy: &DefaultStrategy{RS256JWTStrategy: j.RS256JWTStrategy, Issuer: "https://server.example.com"}}
+
+	token, err := h.GenerateLogoutToken("foo", "peter", "session")
+	require.Nil(t, err)
+
+	parsed, err := j.RS256JWTStrategy.Decode(token)
+	require.Nil(t, err)
+	assert.Equal(t, "logout+jwt", parsed.Header["typ"])
+
+	claims := parsed.Claims.(jwtx.MapClaims)
+	assert.Equal(t, "https://server.example.com", claims["iss"])
+	assert.Equal(t, "foo", claims["aud"])
+	assert.Equal(t, "peter", claims["sub"])
+	assert.Equal(t, "session", claims["sid"])
+	assert.NotEmpty(t, claims["jti"])
+	assert.Nil(t, claims["nonce"])
+	assert.Equal(t, map[string]interface{}{BackChannelLogoutEvent: map[string]interface{}{}}, claims["events"])
+
+	token, err = h.GenerateLogoutToken("foo", "", "session")
+	require.Nil(t, err)
+	parsed, err = j.RS256JWTStrategy.Decode(token)
+	require.Nil(t, err)
+	_, ok := parsed.Claims.(jwtx.MapClaims)["sub"]
+	assert.False(t, ok)
+
+	_, err = h.GenerateLogoutToken("foo", "", "")
+	assert.NotNil(t, err)
+}
+
+func TestBackChannelLogoutHandler_Logout(t *testing.T) {
+	sender := &backChannelTestSender{sent: map[string][]string{}, fail: map[string]bool{"https://bar.example.com/logout": true}}
+	store := backChannelTestStorage{}
+	h := &BackChannelLogoutHandler{
+		IDTokenStrategy: &DefaultStrategy{RS256JWTStrategy: j.RS256JWTStrategy},
+		Sender:          sender,
+		Storage:         store,
+		MaxAttempts:     2,
+		RetryInterval:   -time.Minute,
+	}
+
+	clients := []fosite.Client{
+		&fosite.DefaultClient{ID: "foo", BackChannelLogoutURI: "https://foo.example.com/logout"},
+		&fosite.DefaultClient{ID: "bar", BackChannelLogoutURI: "https://bar.example.com/logout"},
+		&fosite.DefaultClient{ID: "baz", BackChannelLogoutURI: "https://baz.example.com/logout", BackChannelLogoutSessionRequired: true},
+		&fosite.DefaultClient{ID: "none"},
+	}
+
+	require.Nil(t, h.Logout(nil, clients, "peter", ""))
+	assert.Len(t, sender.sent["https://foo.example.com/logout"], 1)
+	assert.Len(t, sender.sent["https://baz.example.com/logout"], 0)
+	require.Len(t, store, 1)
+	for _, d := range store {
+		assert.Equal(t, "bar", d.ClientID)
+		assert.Equal(t, 1, d.Attempts)
+	}
+
+	// The second attempt fails as well and the delivery is dropped.
+	require.Nil(t, h.RetryPending(nil))
+	assert.Len(t, store, 0)
+
+	require.Nil(t, h.Logout(nil, clients, "peter", "session"))
+	assert.Len(t, sender.sent["https://baz.example.com/logout"], 1)
+	require.Len(t, store, 1)
+
+	// The retry succeeds and the delivery is removed.
+	sender.fail = map[string]bool{}
+	require.Nil(t, h.RetryPending(nil))
+	assert.Len(t, store, 0)
+	assert.Len(t, sender.sent["https://bar.example.com/logout"], 1)
+
+	// Without a queue, the delivery error is returned.
+	sender.fail = map[string]bool{"https://bar.example.com/logout": true}
+	h.Storage = nil
+	assert.NotNil(t, h.Logout(nil, clients, "peter", "session"))
+}
+
+func TestDefaultBackChannelLogoutSender(t *testing.T) {
+	var received string
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		received = r.PostFormValue("logout_token")
+		if received == "fail" {
+			rw.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	s := &DefaultBackChannelLogoutSender{}
+	assert.Nil(t, s.Send(nil, ts.URL, "token"))
+	assert.Equal(t, "token", received)
+	assert.NotNil(t, s.Send(nil, ts.URL, "fail"))
+}
@@ -0,0 +1,121 @@
+package openid
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/ory/fosite"
+	"github.com/pkg/errors"
+)
+
+// DefaultFrontChannelLogoutTemplate renders each front-channel logout URI in a hidden iframe.
+var DefaultFrontChannelLogoutTemplate = template.Must(template.New("frontchannel_logout").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Logging out</title></head>
+<body>
+{{ range . }}<iframe src="{{ . }}" style="display:none" width="0" height="0"></iframe>
+{{ end }}</body>
+</html>
+`))
+
+// FrontChannelLogoutHandler renders the logout page of OpenID Connect Front-Channel Logout, see
+// https://openid.net/specs/openid-connect-frontchannel-1_0.html
+type FrontChannelLogoutHandler struct {
+	// Store is used to look up the clients of a login session.
+	Store fosite.Storage
+
+	// LoginSessionStorage tracks which clients took part in a login session.
+	LoginSessionStorage LoginSessionStorage
+
+	// Issuer is added as the iss query parameter for clients requiring the session.
+	Issuer string
+
+	// Template renders the logout page from the list of logout URIs. Defaults to DefaultFrontChannelLogoutTemplate.
+	Template *template.Template
+}
+
+// GetLoginSessionClients returns the clients that took part in the login session. Clients that no longer exist are
+// skipped. The result can also be passed to BackChannelLogoutHandler.Logout.
+func (h *FrontChannelLogoutHandler) GetLoginSessionClients(ctx context.Context, sessionID string) ([]fosite.Client, error) {
+	ids, err := h.LoginSessionStorage.GetLoginSessionClients(ctx, sessionID)
+	if errors.Cause(err) == fosite.ErrNotFound {
+		return []fosite.Client{}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(fosite.ErrServerError, err.Error())
+	}
+
+	clients := []fosite.Client{}
+	for _, id := range ids {
+		client, err := h.Store.GetClient(ctx, id)
+		if errors.Cause(err) == fosite.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrap(fosite.ErrServerError, err.Error())
+		}
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+// GetFrontChannelLogoutURIs returns the front-channel logout URIs of the clients as defined in
+// https://openid.net/specs/openid-connect-frontchannel-1_0.html#RPLogout
+//
+//   The OP renders <iframe src="frontchannel_logout_uri"> in a page with
+//   the RP's logout URI as the source in the iframe. [...] If the OP
+//   supports session IDs and the RP's frontchannel_logout_session_required
+//   parameter is true, the OP MUST add iss and sid query parameters to the
+//   frontchannel_logout_uri.
+//
+// Clients requiring the session are skipped if the session id is unknown.
+func (h *FrontChannelLogoutHandler) GetFrontChannelLogoutURIs(clients []fosite.Client, sessionID string) []string {
+	uris := []string{}
+	for _, client := range clients {
+		fc, ok := client.(fosite.ClientWithFrontChannelLogout)
+		if !ok || fc.GetFrontChannelLogoutURI() == "" {
+			continue
+		}
+
+		if !fc.IsFrontChannelLogoutSessionRequired() {
+			uris = append(uris, fc.GetFrontChannelLogoutURI())
+			continue
+		} else if sessionID == "" {
+			continue
+		}
+
+		uri, err := url.Parse(fc.GetFrontChannelLogoutURI())
+		if err != nil {
+			continue
+		}
+		query := uri.Query()
+		query.Set("iss", h.Issuer)
+		query.Set("sid", sessionID)
+		uri.RawQuery = query.Encode()
+		uris = append(uris, uri.String())
+	}
+	return uris
+}
+
+// WriteFrontChannelLogoutPage renders a page embedding the front-channel logout URIs of the clients, e.g. those
+// returned by GetLoginSessionClients, in hidden iframes. Forgetting the login session is left to the caller, which
+// usually also notifies the clients using BackChannelLogoutHandler first.
+func (h *FrontChannelLogoutHandler) WriteFrontChannelLogoutPage(rw http.ResponseWriter, clients []fosite.Client, sessionID string) error {
+	t := h.Template
+	if t == nil {
+		t = DefaultFrontChannelLogoutTemplate
+	}
+
+	var page bytes.Buffer
+	if err := t.Execute(&page, h.GetFrontChannelLogoutURIs(clients, sessionID)); err != nil {
+		return errors.Wrap(fosite.ErrServerError, err.Error())
+	}
+
+	rw.Header().Set("Content-Type", "text/html;charset=UTF-8")
+	// The page must not be cached, see https://openid.net/specs/openid-connect-frontchannel-1_0.html#RPLogout
+	rw.Header().Set("Cache-Control", "no-cache, no-store")
+	rw.Header().Set("Pragma", "no-cache")
+	_, _ = rw.Write(page.Bytes())
+	return nil
+}
@@ -0,0 +1,62 @@
+package openid
+
+import (
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/storage"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontChannelLogoutHandler(t *testing.T) {
+	store := storage.NewMemoryStore()
+	store.Clients["foo"] = &fosite.DefaultClient{ID: "foo", FrontChannelLogoutURI: "https://foo.example.com/logout"}
+	store.Clients["bar"] = &fosite.DefaultClient{ID: "bar", FrontChannelLogoutURI: "https://bar.example.com/logout?a=b", FrontChannelLogoutSessionRequired: true}
+	store.Clients["baz"] = &fosite.DefaultClient{ID: "baz"}
+
+	strategy := &DefaultStrategy{RS256JWTStrategy: j.RS256JWTStrategy, LoginSessionStorage: store}
+	for _, id := range []string{"foo", "bar", "baz", "deleted", "foo"} {
+		req := fosite.NewAccessRequest(&DefaultSession{
+			Claims:  &jwt.IDTokenClaims{Subject: "peter", SessionID: "session"},
+			Headers: &jwt.Headers{},
+		})
+		req.Client = &fosite.DefaultClient{ID: id}
+		_, err := strategy.GenerateIDToken(nil, req)
+		require.Nil(t, err)
+	}
+	assert.Equal(t, []string{"foo", "bar", "baz", "deleted"}, store.LoginSessionClients["session"])
+
+	h := &FrontChannelLogoutHandler{Store: store, LoginSessionStorage: store, Issuer: "https://server.example.com"}
+	clients, err := h.GetLoginSessionClients(nil, "session")
+	require.Nil(t, err)
+	require.Len(t, clients, 3)
+
+	assert.Equal(t, []string{
+		"https://foo.example.com/logout",
+		"https://bar.example.com/logout?a=b&iss=https%3A%2F%2Fserver.example.com&sid=session",
+	}, h.GetFrontChannelLogoutURIs(clients, "session"))
+	assert.Equal(t, []string{"https://foo.example.com/logout"}, h.GetFrontChannelLogoutURIs(clients, ""))
+
+	rw := httptest.NewRecorder()
+	require.Nil(t, h.WriteFrontChannelLogoutPage(rw, clients, "session"))
+	assert.Equal(t, "text/html;charset=UTF-8", rw.Header().Get("Content-Type"))
+	assert.Equal(t, 2, strings.Count(rw.Body.String(), "<iframe"))
+	assert.Contains(t, rw.Body.String(), `src="https://bar.example.com/logout?a=b&amp;iss=https%3A%2F%2Fserver.example.com&amp;sid=session"`)
+
+	// The login session is kept, e.g. for back-channel logout, until the caller deletes it.
+	clients, err = h.GetLoginSessionClients(nil, "session")
+	require.Nil(t, err)
+	assert.Len(t, clients, 3)
+
+	h.Template = template.Must(template.New("broken").Parse(`{{ .Missing }}`))
+	rw = httptest.NewRecorder()
+	assert.Equal(t, fosite.ErrServerError, errors.Cause(h.WriteFrontChannelLogoutPage(rw, clients, "session")))
+	assert.Empty(t, rw.Body.String())
+	assert.Empty(t, rw.Header().Get("Content-Type"))
+}
@@ -0,0 +1,213 @@
+package openid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/internal"
+	"github.com/ory/fosite/storage"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logoutTestRevoker struct {
+	subject, clientID string
+}
+
+func (r *logoutTestRevoker) RevokeTokensBySubjectAndClient(_ context.Context, subject, clientID string) error {
+	r.subject, r.clientID = subject, clientID
+	return nil
+}
+
+func TestLogoutHandler(t *testing.T) {
+	store := storage.NewMemoryStore()
+	store.Clients["foo"] = &fosite.DefaultClient{ID: "foo", PostLogoutRedirectURIs: []string{"https://foo.example.com/logged-out"}}
+	store.Clients["bar"] = &fosite.DefaultClient{ID: "bar"}
+
+	h := &LogoutHandler{
+		IDTokenStrategy: &DefaultStrategy{RS256JWTStrategy: j.RS256JWTStrategy, Issuer: "https://server.example.com"},
+		Store:           store,
+	}
+
+	newIDToken := func(strategy *jwt.RS256JWTStrategy, issuer, audience string, exp time.Time) string {
+		token, _, err := strategy.Generate((&jwt.IDTokenClaims{
+			Issuer:    issuer,
+			Subject:   "peter",
+			Audience:  audience,
+			SessionID: "session",
+			ExpiresAt: exp,
+			IssuedAt:  time.Now(),
+		}).ToMapClaims(), &jwt.Headers{})
+		require.Nil(t, err)
+		return token
+	}
+	valid := newIDToken(j.RS256JWTStrategy, "https://server.example.com", "foo", time.Now().Add(time.Hour))
+	expired := newIDToken(j.RS256JWTStrategy, "https://server.example.com", "foo", time.Now().Add(-time.Hour))
+
+	for k, c := range []struct {
+		description    string
+		method         string
+		query          url.Values
+		expectErr      error
+		expectClient   string
+		expectSubject  string
+		expectRedirect string
+	}{
+		{
+			description: "should pass without parameters",
+		},
+		{
+			description:    "should pass with id_token_hint and post_logout_redirect_uri",
+			query:          url.Values{"id_token_hint": {valid}, "post_logout_redirect_uri": {"https://foo.example.com/logged-out"}, "state": {"some-state"}},
+			expectClient:   "foo",
+			expectSubject:  "peter",
+			expectRedirect: "https://foo.example.com/logged-out?state=some-state",
+		},
+		{
+			description:   "should pass with an expired id_token_hint",
+			query:         url.Values{"id_token_hint": {expired}},
+			expectClient:  "foo",
+			expectSubject: "peter",
+		},
+		{
+			description:    "should pass with client_id and post_logout_redirect_uri",
+			query:          url.Values{"client_id": {"foo"}, "post_logout_redirect_uri": {"https://foo.example.com/logged-out"}},
+			expectClient:   "foo",
+			expectRedirect: "https://foo.example.com/logged-out",
+		},
+		{
+			description: "should fail because the method is not allowed",
+			method:      "PUT",
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description: "should fail because id_token_hint was signed with another key",
+			query:       url.Values{"id_token_hint": {newIDToken(&jwt.RS256JWTStrategy{PrivateKey: internal.MustRSAKey()}, "https://server.example.com", "foo", time.Now().Add(time.Hour))}},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description: "should fail because id_token_hint was issued by another issuer",
+			query:       url.Values{"id_token_hint": {newIDToken(j.RS256JWTStrategy, "https://other.example.com", "foo", time.Now().Add(time.Hour))}},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description: "should fail because client_id does not match id_token_hint",
+			query:       url.Values{"id_token_hint": {valid}, "client_id": {"bar"}},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description: "should fail because the client does not exist",
+			query:       url.Values{"client_id": {"baz"}},
+			expectErr:   fosite.ErrInvalidClient,
+		},
+		{
+			description: "should fail because post_logout_redirect_uri requires a client",
+			query:       url.Values{"post_logout_redirect_uri": {"https://foo.example.com/logged-out"}},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description: "should fail because post_logout_redirect_uri is not registered",
+			query:       url.Values{"client_id": {"foo"}, "post_logout_redirect_uri": {"https://foo.example.com/other"}},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description: "should fail because the client has no post logout redirect uris",
+			query:       url.Values{"client_id": {"bar"}, "post_logout_redirect_uri": {"https://foo.example.com/logged-out"}},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+	} {
+		method := c.method
+		if method == "" {
+			method = "GET"
+		}
+		r, err := http.NewRequest(method, "https://server.example.com/oauth2/sessions/logout?"+c.query.Encode(), nil)
+		require.Nil(t, err)
+
+		lr, err := h.NewLogoutRequest(nil, r)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expectErr != nil {
+			continue
+		}
+
+		if c.expectClient == "" {
+			assert.Nil(t, lr.Client, "(%d) %s", k, c.description)
+		} else {
+			assert.Equal(t, c.expectClient, lr.Client.GetID(), "(%d) %s", k, c.description)
+		}
+		assert.Equal(t, c.expectSubject, lr.Subject, "(%d) %s", k, c.description)
+
+		rw := httptest.NewRecorder()
+		h.WriteLogoutResponse(rw, lr)
+		if c.expectRedirect == "" {
+			assert.Equal(t, http.StatusOK, rw.Code, "(%d) %s", k, c.description)
+		} else {
+			assert.Equal(t, http.StatusFound, rw.Code, "(%d) %s", k, c.description)
+			assert.Equal(t, c.expectRedirect, rw.Header().Get("Location"), "(%d) %s", k, c.description)
+		}
+	}
+}
+
+func TestLogoutHandler_Logout(t *testing.T) {
+	var hooked *LogoutRequest
+	revoker := &logoutTestRevoker{}
+	h := &LogoutHandler{
+		Hook: func(_ context.Context, lr *LogoutRequest) error {
+			hooked = lr
+			return nil
+		},
+		TokenRevoker: revoker,
+	}
+
+	lr := &LogoutRequest{Client: &fosite.DefaultClient{ID: "foo"}, Subject: "peter", SessionID: "session"}
+	require.Nil(t, h.Logout(nil, lr))
+	assert.Equal(t, lr, hooked)
+	assert.Equal(t, "peter", revoker.subject)
+	assert.Equal(t, "foo", revoker.clientID)
+
+	h.Hook = func(_ context.Context, _ *LogoutRequest) error {
+		return errors.WithStack(fosite.ErrServerError)
+	}
+	assert.Equal(t, fosite.ErrServerError, errors.Cause(h.Logout(nil, lr)))
+}
+
+func TestLogoutHandler_LogoutPairwise(t *testing.T) {
+	hooked := false
+	revoker := &logoutTestRevoker{}
+	h := &LogoutHandler{
+		Hook: func(_ context.Context, _ *LogoutRequest) error {
+			hooked = true
+			return nil
+		},
+		TokenRevoker: revoker,
+	}
+
+	lr := &LogoutRequest{
+		Client:    &fosite.DefaultClient{ID: "foo", SubjectType: fosite.SubjectTypePairwise},
+		Subject:   "pairwise-peter",
+		SessionID: "session",
+	}
+	assert.Equal(t, fosite.ErrServerError, errors.Cause(h.Logout(nil, lr)))
+	assert.False(t, hooked)
+	assert.Empty(t, revoker.subject)
+
+	h.SubjectResolver = func(_ context.Context, lr *LogoutRequest) (string, error) {
+		if lr.SessionID != "session" {
+			return "", nil
+		}
+		return "peter", nil
+	}
+	require.Nil(t, h.Logout(nil, lr))
+	assert.True(t, hooked)
+	assert.Equal(t, "peter", revoker.subject)
+	assert.Equal(t, "foo", revoker.clientID)
+
+	lr.SessionID = "unknown"
+	assert.Equal(t, fosite.ErrServerError, errors.Cause(h.Logout(nil, lr)))
+}
@@ -22,3 +22,23 @@ type OpenIDConnectRequestStorage interface {
 	// DeleteOpenIDConnectSession removes an open id connect session from the store.
 	DeleteOpenIDConnectSession(ctx context.Context, authorizeCode string) error
 }
+
+// OpenIDConnectSessionBulkRevocationStorage is an optional extension of OpenIDConnectRequestStorage for storages that
+// delete all OpenID Connect sessions of a subject or client at once.
+type OpenIDConnectSessionBulkRevocationStorage interface {
+	// DeleteOpenIDConnectSessions removes all open id connect sessions matching the filter from the store.
+	DeleteOpenIDConnectSessions(ctx context.Context, filter fosite.BulkRevocationFilter) error
+}
+
+// LoginSessionStorage tracks which clients took part in a login session, identified by the sid claim of the ID
+// tokens issued during the session. It is used to notify these clients when the user logs out.
+type LoginSessionStorage interface {
+	// AddLoginSessionClient records that an ID token was issued to the client during the login session.
+	AddLoginSessionClient(ctx context.Context, sessionID string, clientID string) error
+
+	// GetLoginSessionClients returns the IDs of all clients that took part in the login session.
+	GetLoginSessionClients(ctx context.Context, sessionID string) ([]string, error)
+
+	// DeleteLoginSession removes the login session after the user logged out.
+	DeleteLoginSession(ctx context.Context, sessionID string) error
+}
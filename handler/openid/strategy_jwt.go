@@ -101,22 +101,52 @@ func (s *DefaultSession) IDTokenClaims() *jwt.IDTokenClaims {
 type DefaultStrategy struct {
 	*jwt.RS256JWTStrategy
 
+	// Expiry defines the lifetime of an id token, unless the client overrides it. Defaults to one hour.
 	Expiry time.Duration
 	Issuer string
+
+	// LoginSessionStorage, if set, records the clients ID tokens with a sid claim were issued to, see
+	// FrontChannelLogoutHandler and BackChannelLogoutHandler.
+	LoginSessionStorage LoginSessionStorage
+
+	// SubjectIdentifierStrategy, if set, transforms the subject claim, e.g. into a pairwise subject identifier. The
+	// subject stored in the session is not modified.
+	SubjectIdentifierStrategy fosite.SubjectIdentifierStrategy
+}
+
+// GetSubjectIdentifier returns the subject identifier the client sees for the subject. Hosts should use it for the
+// "sub" claim of UserInfo responses, which must match the ID token's subject.
+func (h DefaultStrategy) GetSubjectIdentifier(ctx context.Context, client fosite.Client, subject string) (string, error) {
+	return fosite.GetSubjectIdentifier(ctx, h.SubjectIdentifierStrategy, client, subject)
 }
 
-func (h DefaultStrategy) GenerateIDToken(_ context.Context, requester fosite.Requester) (token string, err error) {
+func (h DefaultStrategy) GenerateIDToken(ctx context.Context, requester fosite.Requester) (token string, err error) {
 	if h.Expiry == 0 {
 		h.Expiry = defaultExpiryTime
 	}
+	h.Expiry = fosite.GetEffectiveLifespan(requester.GetClient(), getGrantType(requester), fosite.IDToken, h.Expiry)
 
 	sess, ok := requester.GetSession().(Session)
 	if !ok {
 		return "", errors.New("Session must be of type strategy.Session")
 	}
 
+	maxAgeRequested := requester.GetRequestForm().Get("max_age") != ""
+	if oc, ok := requester.GetClient().(fosite.OpenIDConnectClient); ok {
+		// Only RS256 signed ID tokens are supported.
+		if alg := oc.GetIDTokenSignedResponseAlgorithm(); alg != "RS256" {
+			return "", errors.Errorf("ID token signing algorithm %s requested by the client is not supported", alg)
+		} else if oc.GetIDTokenEncryptedResponseAlgorithm() != "" {
+			return "", errors.New("ID token encryption requested by the client is not supported")
+		}
+
+		// The client's default_max_age applies if the request did not contain max_age, and require_auth_time
+		// demands the auth_time claim.
+		maxAgeRequested = maxAgeRequested || oc.GetDefaultMaxAge() > 0 || oc.IsAuthTimeRequired()
+	}
+
 	claims := sess.IDTokenClaims()
-	if requester.GetRequestForm().Get("max_age") != "" && (claims.AuthTime.IsZero() || claims.AuthTime.After(time.Now())) {
+	if maxAgeRequested && (claims.AuthTime.IsZero() || claims.AuthTime.After(time.Now())) {
 		return "", errors.New("Authentication time claim is required when max_age is set and can not be in the future")
 	}
 
@@ -153,6 +183,32 @@ func (h DefaultStrategy) GenerateIDToken(_ context.Context, requester fosite.Req
 	claims.Audience = requester.GetClient().GetID()
 	claims.IssuedAt = time.Now()
 
-	token, _, err = h.RS256JWTStrategy.Generate(claims.ToMapClaims(), sess.IDTokenHeaders())
-	return token, err
+	subject, err := h.GetSubjectIdentifier(ctx, requester.GetClient(), claims.Subject)
+	if err != nil {
+		return "", err
+	}
+
+	mapClaims := claims.ToMapClaims()
+	mapClaims["sub"] = subject
+	token, _, err = h.RS256JWTStrategy.Generate(mapClaims, sess.IDTokenHeaders())
+	if err != nil {
+		return "", err
+	}
+
+	if h.LoginSessionStorage != nil && claims.SessionID != "" {
+		if err := h.LoginSessionStorage.AddLoginSessionClient(ctx, claims.SessionID, requester.GetClient().GetID()); err != nil {
+			return "", err
+		}
+	}
+
+	return token, nil
+}
+
+// getGrantType returns the grant type the id token is issued with. ID tokens issued by the authorize endpoint are
+// issued using the implicit grant.
+func getGrantType(requester fosite.Requester) string {
+	if ar, ok := requester.(fosite.AccessRequester); ok && len(ar.GetGrantTypes()) == 1 {
+		return ar.GetGrantTypes()[0]
+	}
+	return "implicit"
 }
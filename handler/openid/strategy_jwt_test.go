@@ -4,9 +4,11 @@ import (
 	"testing"
 	"time"
 
+	jwtx "github.com/dgrijalva/jwt-go"
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/token/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJWTStrategy_GenerateIDToken(t *testing.T) {
@@ -97,3 +99,104 @@ func TestJWTStrategy_GenerateIDToken(t *testing.T) {
 		}
 	}
 }
+
+func TestJWTStrategy_GenerateIDTokenWithClientLifespan(t *testing.T) {
+	req := fosite.NewAccessRequest(&DefaultSession{
+		Claims: &jwt.IDTokenClaims{
+			Subject: "peter",
+		},
+		Headers: &jwt.Headers{},
+	})
+	req.GrantTypes = fosite.Arguments{"authorization_code"}
+	req.Client = &fosite.DefaultClientWithCustomTokenLifespans{
+		DefaultClient: &fosite.DefaultClient{ID: "foo"},
+		TokenLifespans: map[string]map[fosite.TokenType]time.Duration{
+			"authorization_code": {fosite.IDToken: 5 * time.Minute},
+		},
+	}
+
+	_, err := j.GenerateIDToken(nil, req)
+	assert.Nil(t, err, "%s", err)
+
+	exp := req.GetSession().(*DefaultSession).IDTokenClaims().ExpiresAt
+	assert.True(t, exp.Before(time.Now().Add(6*time.Minute)), "%s", exp)
+	assert.True(t, exp.After(time.Now().Add(4*time.Minute)), "%s", exp)
+}
+
+func TestJWTStrategy_GenerateIDTokenWithPairwiseSubject(t *testing.T) {
+	sess := &DefaultSession{
+		Claims: &jwt.IDTokenClaims{
+			Subject: "peter",
+		},
+		Headers: &jwt.Headers{},
+	}
+	req := fosite.NewAccessRequest(sess)
+	req.Client = &fosite.DefaultClient{ID: "foo", SubjectType: fosite.SubjectTypePairwise, RedirectURIs: []string{"https://foo.example.com/cb"}}
+
+	strategy := *j
+	strategy.SubjectIdentifierStrategy = &fosite.PairwiseSubjectIdentifierStrategy{Salt: []byte("salt")}
+	token, err := strategy.GenerateIDToken(nil, req)
+	require.Nil(t, err, "%s", err)
+
+	expected, err := strategy.GetSubjectIdentifier(nil, req.Client, "peter")
+	require.Nil(t, err)
+	assert.NotEqual(t, "peter", expected)
+
+	decoded, err := strategy.RS256JWTStrategy.Decode(token)
+	require.Nil(t, err)
+	assert.Equal(t, expected, decoded.Claims.(jwtx.MapClaims)["sub"])
+	assert.Equal(t, "peter", sess.Claims.Subject)
+}
+
+func TestJWTStrategy_GenerateIDTokenWithOpenIDConnectClient(t *testing.T) {
+	for k, c := range []struct {
+		description string
+		client      *fosite.DefaultOpenIDConnectClient
+		authTime    time.Time
+		expectErr   bool
+	}{
+		{
+			description: "should pass with the default metadata",
+			client:      &fosite.DefaultOpenIDConnectClient{DefaultClient: &fosite.DefaultClient{ID: "foo"}},
+		},
+		{
+			description: "should fail because the signing algorithm is not supported",
+			client:      &fosite.DefaultOpenIDConnectClient{DefaultClient: &fosite.DefaultClient{ID: "foo"}, IDTokenSignedResponseAlgorithm: "ES256"},
+			authTime:    time.Now(),
+			expectErr:   true,
+		},
+		{
+			description: "should fail because ID token encryption is not supported",
+			client:      &fosite.DefaultOpenIDConnectClient{DefaultClient: &fosite.DefaultClient{ID: "foo"}, IDTokenEncryptedResponseAlgorithm: "RSA-OAEP"},
+			authTime:    time.Now(),
+			expectErr:   true,
+		},
+		{
+			description: "should fail because default_max_age requires auth_time",
+			client:      &fosite.DefaultOpenIDConnectClient{DefaultClient: &fosite.DefaultClient{ID: "foo"}, DefaultMaxAge: 60},
+			expectErr:   true,
+		},
+		{
+			description: "should fail because require_auth_time requires auth_time",
+			client:      &fosite.DefaultOpenIDConnectClient{DefaultClient: &fosite.DefaultClient{ID: "foo"}, RequireAuthTime: true},
+			expectErr:   true,
+		},
+		{
+			description: "should pass because auth_time is set",
+			client:      &fosite.DefaultOpenIDConnectClient{DefaultClient: &fosite.DefaultClient{ID: "foo"}, DefaultMaxAge: 60, RequireAuthTime: true},
+			authTime:    time.Now(),
+		},
+	} {
+		req := fosite.NewAccessRequest(&DefaultSession{
+			Claims: &jwt.IDTokenClaims{
+				Subject:  "peter",
+				AuthTime: c.authTime,
+			},
+			Headers: &jwt.Headers{},
+		})
+		req.Client = c.client
+
+		_, err := j.GenerateIDToken(nil, req)
+		assert.Equal(t, c.expectErr, err != nil, "(%d) %s\n%s", k, c.description, err)
+	}
+}
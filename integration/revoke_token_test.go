@@ -51,3 +51,27 @@ func runRevokeTokenTest(t *testing.T, strategy oauth2.AccessTokenStrategy) {
 	require.Len(t, errs, 0)
 	assert.Equal(t, http.StatusUnauthorized, hres.StatusCode)
 }
+
+func TestRevokeTokensByClient(t *testing.T) {
+	f := compose.Compose(new(compose.Config), fositeStore, hmacStrategy, nil, compose.OAuth2ClientCredentialsGrantFactory, compose.OAuth2TokenIntrospectionFactory, compose.OAuth2TokenRevocationFactory)
+	ts := mockServer(t, f, &fosite.DefaultSession{})
+	defer ts.Close()
+
+	oauthClient := newOAuth2AppClient(ts)
+	token, err := oauthClient.Token(goauth.NoContext)
+	require.Nil(t, err)
+
+	hres, _, errs := gorequest.New().Get(ts.URL+"/info").
+		Set("Authorization", "bearer "+token.AccessToken).
+		End()
+	require.Len(t, errs, 0)
+	assert.Equal(t, http.StatusNoContent, hres.StatusCode)
+
+	require.Nil(t, f.RevokeTokensByClient(nil, oauthClient.ClientID))
+
+	hres, _, errs = gorequest.New().Get(ts.URL+"/info").
+		Set("Authorization", "bearer "+token.AccessToken).
+		End()
+	require.Len(t, errs, 0)
+	assert.Equal(t, http.StatusUnauthorized, hres.StatusCode)
+}
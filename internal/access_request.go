@@ -60,6 +60,26 @@ func (_mr *_MockAccessRequesterRecorder) GetGrantTypes() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantTypes")
 }
 
+func (_m *MockAccessRequester) GetGrantedAudience() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetGrantedAudience")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockAccessRequesterRecorder) GetGrantedAudience() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedAudience")
+}
+
+func (_m *MockAccessRequester) GetGrantedAuthorizationDetails() fosite.AuthorizationDetails {
+	ret := _m.ctrl.Call(_m, "GetGrantedAuthorizationDetails")
+	ret0, _ := ret[0].(fosite.AuthorizationDetails)
+	return ret0
+}
+
+func (_mr *_MockAccessRequesterRecorder) GetGrantedAuthorizationDetails() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedAuthorizationDetails")
+}
+
 func (_m *MockAccessRequester) GetGrantedScopes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetGrantedScopes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -100,6 +120,26 @@ func (_mr *_MockAccessRequesterRecorder) GetRequestedAt() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAt")
 }
 
+func (_m *MockAccessRequester) GetRequestedAudience() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetRequestedAudience")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockAccessRequesterRecorder) GetRequestedAudience() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAudience")
+}
+
+func (_m *MockAccessRequester) GetRequestedAuthorizationDetails() fosite.AuthorizationDetails {
+	ret := _m.ctrl.Call(_m, "GetRequestedAuthorizationDetails")
+	ret0, _ := ret[0].(fosite.AuthorizationDetails)
+	return ret0
+}
+
+func (_mr *_MockAccessRequesterRecorder) GetRequestedAuthorizationDetails() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAuthorizationDetails")
+}
+
 func (_m *MockAccessRequester) GetRequestedScopes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetRequestedScopes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -120,6 +160,22 @@ func (_mr *_MockAccessRequesterRecorder) GetSession() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetSession")
 }
 
+func (_m *MockAccessRequester) GrantAudience(_param0 string) {
+	_m.ctrl.Call(_m, "GrantAudience", _param0)
+}
+
+func (_mr *_MockAccessRequesterRecorder) GrantAudience(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GrantAudience", arg0)
+}
+
+func (_m *MockAccessRequester) GrantAuthorizationDetail(_param0 fosite.AuthorizationDetail) {
+	_m.ctrl.Call(_m, "GrantAuthorizationDetail", _param0)
+}
+
+func (_mr *_MockAccessRequesterRecorder) GrantAuthorizationDetail(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GrantAuthorizationDetail", arg0)
+}
+
 func (_m *MockAccessRequester) GrantScope(_param0 string) {
 	_m.ctrl.Call(_m, "GrantScope", _param0)
 }
@@ -136,6 +192,22 @@ func (_mr *_MockAccessRequesterRecorder) Merge(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Merge", arg0)
 }
 
+func (_m *MockAccessRequester) SetRequestedAudience(_param0 fosite.Arguments) {
+	_m.ctrl.Call(_m, "SetRequestedAudience", _param0)
+}
+
+func (_mr *_MockAccessRequesterRecorder) SetRequestedAudience(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetRequestedAudience", arg0)
+}
+
+func (_m *MockAccessRequester) SetRequestedAuthorizationDetails(_param0 fosite.AuthorizationDetails) {
+	_m.ctrl.Call(_m, "SetRequestedAuthorizationDetails", _param0)
+}
+
+func (_mr *_MockAccessRequesterRecorder) SetRequestedAuthorizationDetails(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetRequestedAuthorizationDetails", arg0)
+}
+
 func (_m *MockAccessRequester) SetRequestedScopes(_param0 fosite.Arguments) {
 	_m.ctrl.Call(_m, "SetRequestedScopes", _param0)
 }
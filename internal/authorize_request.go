@@ -60,6 +60,26 @@ func (_mr *_MockAuthorizeRequesterRecorder) GetClient() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetClient")
 }
 
+func (_m *MockAuthorizeRequester) GetGrantedAudience() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetGrantedAudience")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetGrantedAudience() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedAudience")
+}
+
+func (_m *MockAuthorizeRequester) GetGrantedAuthorizationDetails() fosite.AuthorizationDetails {
+	ret := _m.ctrl.Call(_m, "GetGrantedAuthorizationDetails")
+	ret0, _ := ret[0].(fosite.AuthorizationDetails)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetGrantedAuthorizationDetails() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedAuthorizationDetails")
+}
+
 func (_m *MockAuthorizeRequester) GetGrantedScopes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetGrantedScopes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -110,6 +130,26 @@ func (_mr *_MockAuthorizeRequesterRecorder) GetRequestedAt() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAt")
 }
 
+func (_m *MockAuthorizeRequester) GetRequestedAudience() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetRequestedAudience")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetRequestedAudience() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAudience")
+}
+
+func (_m *MockAuthorizeRequester) GetRequestedAuthorizationDetails() fosite.AuthorizationDetails {
+	ret := _m.ctrl.Call(_m, "GetRequestedAuthorizationDetails")
+	ret0, _ := ret[0].(fosite.AuthorizationDetails)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetRequestedAuthorizationDetails() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAuthorizationDetails")
+}
+
 func (_m *MockAuthorizeRequester) GetRequestedScopes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetRequestedScopes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -150,6 +190,22 @@ func (_mr *_MockAuthorizeRequesterRecorder) GetState() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetState")
 }
 
+func (_m *MockAuthorizeRequester) GrantAudience(_param0 string) {
+	_m.ctrl.Call(_m, "GrantAudience", _param0)
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GrantAudience(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GrantAudience", arg0)
+}
+
+func (_m *MockAuthorizeRequester) GrantAuthorizationDetail(_param0 fosite.AuthorizationDetail) {
+	_m.ctrl.Call(_m, "GrantAuthorizationDetail", _param0)
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GrantAuthorizationDetail(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GrantAuthorizationDetail", arg0)
+}
+
 func (_m *MockAuthorizeRequester) GrantScope(_param0 string) {
 	_m.ctrl.Call(_m, "GrantScope", _param0)
 }
@@ -176,6 +232,22 @@ func (_mr *_MockAuthorizeRequesterRecorder) Merge(arg0 interface{}) *gomock.Call
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Merge", arg0)
 }
 
+func (_m *MockAuthorizeRequester) SetRequestedAudience(_param0 fosite.Arguments) {
+	_m.ctrl.Call(_m, "SetRequestedAudience", _param0)
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) SetRequestedAudience(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetRequestedAudience", arg0)
+}
+
+func (_m *MockAuthorizeRequester) SetRequestedAuthorizationDetails(_param0 fosite.AuthorizationDetails) {
+	_m.ctrl.Call(_m, "SetRequestedAuthorizationDetails", _param0)
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) SetRequestedAuthorizationDetails(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetRequestedAuthorizationDetails", arg0)
+}
+
 func (_m *MockAuthorizeRequester) SetRequestedScopes(_param0 fosite.Arguments) {
 	_m.ctrl.Call(_m, "SetRequestedScopes", _param0)
 }
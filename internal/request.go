@@ -50,6 +50,26 @@ func (_mr *_MockRequesterRecorder) GetClient() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetClient")
 }
 
+func (_m *MockRequester) GetGrantedAudience() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetGrantedAudience")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockRequesterRecorder) GetGrantedAudience() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedAudience")
+}
+
+func (_m *MockRequester) GetGrantedAuthorizationDetails() fosite.AuthorizationDetails {
+	ret := _m.ctrl.Call(_m, "GetGrantedAuthorizationDetails")
+	ret0, _ := ret[0].(fosite.AuthorizationDetails)
+	return ret0
+}
+
+func (_mr *_MockRequesterRecorder) GetGrantedAuthorizationDetails() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedAuthorizationDetails")
+}
+
 func (_m *MockRequester) GetGrantedScopes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetGrantedScopes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -90,6 +110,26 @@ func (_mr *_MockRequesterRecorder) GetRequestedAt() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAt")
 }
 
+func (_m *MockRequester) GetRequestedAudience() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetRequestedAudience")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockRequesterRecorder) GetRequestedAudience() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAudience")
+}
+
+func (_m *MockRequester) GetRequestedAuthorizationDetails() fosite.AuthorizationDetails {
+	ret := _m.ctrl.Call(_m, "GetRequestedAuthorizationDetails")
+	ret0, _ := ret[0].(fosite.AuthorizationDetails)
+	return ret0
+}
+
+func (_mr *_MockRequesterRecorder) GetRequestedAuthorizationDetails() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAuthorizationDetails")
+}
+
 func (_m *MockRequester) GetRequestedScopes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetRequestedScopes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -110,6 +150,22 @@ func (_mr *_MockRequesterRecorder) GetSession() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetSession")
 }
 
+func (_m *MockRequester) GrantAudience(_param0 string) {
+	_m.ctrl.Call(_m, "GrantAudience", _param0)
+}
+
+func (_mr *_MockRequesterRecorder) GrantAudience(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GrantAudience", arg0)
+}
+
+func (_m *MockRequester) GrantAuthorizationDetail(_param0 fosite.AuthorizationDetail) {
+	_m.ctrl.Call(_m, "GrantAuthorizationDetail", _param0)
+}
+
+func (_mr *_MockRequesterRecorder) GrantAuthorizationDetail(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GrantAuthorizationDetail", arg0)
+}
+
 func (_m *MockRequester) GrantScope(_param0 string) {
 	_m.ctrl.Call(_m, "GrantScope", _param0)
 }
@@ -126,6 +182,22 @@ func (_mr *_MockRequesterRecorder) Merge(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Merge", arg0)
 }
 
+func (_m *MockRequester) SetRequestedAudience(_param0 fosite.Arguments) {
+	_m.ctrl.Call(_m, "SetRequestedAudience", _param0)
+}
+
+func (_mr *_MockRequesterRecorder) SetRequestedAudience(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetRequestedAudience", arg0)
+}
+
+func (_m *MockRequester) SetRequestedAuthorizationDetails(_param0 fosite.AuthorizationDetails) {
+	_m.ctrl.Call(_m, "SetRequestedAuthorizationDetails", _param0)
+}
+
+func (_mr *_MockRequesterRecorder) SetRequestedAuthorizationDetails(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetRequestedAuthorizationDetails", arg0)
+}
+
 func (_m *MockRequester) SetRequestedScopes(_param0 fosite.Arguments) {
 	_m.ctrl.Call(_m, "SetRequestedScopes", _param0)
 }
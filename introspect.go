@@ -13,14 +13,42 @@ type TokenIntrospector interface {
 	IntrospectToken(ctx context.Context, token string, tokenType TokenType, accessRequest AccessRequester, scopes []string) error
 }
 
+// IntrospectedTokenTypeRequester is an optional extension of AccessRequester that records the type of the token
+// found by token introspection. AccessRequest implements it.
+type IntrospectedTokenTypeRequester interface {
+	// GetIntrospectedTokenType returns the type of the introspected token, e.g. AccessToken or RefreshToken.
+	GetIntrospectedTokenType() TokenType
+
+	// SetIntrospectedTokenType sets the type of the introspected token.
+	SetIntrospectedTokenType(tokenType TokenType)
+}
+
+// GetIntrospectedTokenType returns the type of the introspected token, defaulting to AccessToken if the token
+// introspector did not record it.
+func GetIntrospectedTokenType(r AccessRequester) TokenType {
+	if tr, ok := r.(IntrospectedTokenTypeRequester); ok && tr.GetIntrospectedTokenType() != "" {
+		return tr.GetIntrospectedTokenType()
+	}
+	return AccessToken
+}
+
+// SetIntrospectedTokenType records the type of the introspected token if the requester supports it.
+func SetIntrospectedTokenType(r AccessRequester, tokenType TokenType) {
+	if tr, ok := r.(IntrospectedTokenTypeRequester); ok {
+		tr.SetIntrospectedTokenType(tokenType)
+	}
+}
+
 func AccessTokenFromRequest(req *http.Request) string {
 	// Acording to https://tools.ietf.org/html/rfc6750 you can pass tokens through:
 	// - Form-Encoded Body Parameter. Recomended, more likely to appear. e.g.: Authorization: Bearer mytoken123
 	// - URI Query Parameter e.g. access_token=mytoken123
 
+	// DPoP-bound tokens use the DPoP scheme instead, see https://tools.ietf.org/html/rfc9449#section-7.1
+
 	auth := req.Header.Get("Authorization")
 	split := strings.SplitN(auth, " ", 2)
-	if len(split) != 2 || !strings.EqualFold(split[0], "bearer") {
+	if len(split) != 2 || !(strings.EqualFold(split[0], "bearer") || strings.EqualFold(split[0], "dpop")) {
 		// Nothing in Authorization header, try access_token
 		// Empty string returned if there's no such parameter
 		err := req.ParseForm()
@@ -51,5 +79,26 @@ func (f *Fosite) IntrospectToken(ctx context.Context, token string, tokenType To
 		return nil, errors.Wrap(ErrRequestUnauthorized, "No validator felt responsible for validating the token")
 	}
 
+	if audience, ok := IntrospectionAudienceFromContext(ctx); ok && !f.getAudienceMatchingStrategy()(ar.GetGrantedAudience(), audience) {
+		return nil, errors.Wrapf(ErrTokenClaim, "The token was not issued for audience %s", audience)
+	}
+
 	return ar, nil
 }
+
+type introspectionAudienceContextKey struct{}
+
+// NewIntrospectionAudienceContext returns a context which makes IntrospectToken require that the token was granted
+// the audience, so that a resource server only accepts tokens that are meant for it.
+func NewIntrospectionAudienceContext(ctx context.Context, audience string) context.Context {
+	return context.WithValue(ctx, introspectionAudienceContextKey{}, audience)
+}
+
+// IntrospectionAudienceFromContext returns the audience set by NewIntrospectionAudienceContext, if any.
+func IntrospectionAudienceFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	audience, ok := ctx.Value(introspectionAudienceContextKey{}).(string)
+	return audience, ok
+}
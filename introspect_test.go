@@ -102,3 +102,31 @@ func TestIntrospect(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestIntrospectForAudience(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validator := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	f := compose.ComposeAllEnabled(new(compose.Config), storage.NewMemoryStore(), []byte{}, nil).(*Fosite)
+	f.TokenIntrospectionHandlers = TokenIntrospectionHandlers{validator}
+	validator.EXPECT().IntrospectToken(gomock.Any(), "some-token", gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Do(func(ctx context.Context, _ string, _ TokenType, accessRequest AccessRequester, _ []string) {
+		accessRequest.GrantAudience("https://api.example.com/payments")
+	}).Return(nil)
+
+	for k, c := range []struct {
+		audience  string
+		expectErr error
+	}{
+		{audience: "https://api.example.com/payments"},
+		{audience: "https://api.example.com/payments/transfers"},
+		{audience: "https://api.example.com/users", expectErr: ErrTokenClaim},
+		{audience: "", expectErr: ErrTokenClaim},
+	} {
+		_, err := f.IntrospectToken(NewIntrospectionAudienceContext(context.Background(), c.audience), "some-token", AccessToken, nil)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s", k, err, c.expectErr)
+	}
+
+	_, err := f.IntrospectToken(context.Background(), "some-token", AccessToken, nil)
+	assert.NoError(t, err)
+}
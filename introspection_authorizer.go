@@ -0,0 +1,80 @@
+package fosite
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// IntrospectionAuthorizer decides whether a client may introspect a token. It returns an error, usually wrapping
+// ErrRequestForbidden, if the client is not allowed to know about the token. Such lookups are answered like lookups
+// of inactive tokens, see https://tools.ietf.org/html/rfc7662#section-2.2
+//
+//   Note that a properly formed and authorized query for an inactive or
+//   otherwise invalid token (or a token the protected resource is not
+//   allowed to know about) is not considered an error response by this
+//   specification.
+type IntrospectionAuthorizer interface {
+	AuthorizeIntrospection(ctx context.Context, client Client, token AccessRequester) error
+}
+
+// IntrospectionAuthorizerFunc is an adapter to allow the use of ordinary functions as IntrospectionAuthorizer.
+type IntrospectionAuthorizerFunc func(ctx context.Context, client Client, token AccessRequester) error
+
+func (f IntrospectionAuthorizerFunc) AuthorizeIntrospection(ctx context.Context, client Client, token AccessRequester) error {
+	return f(ctx, client, token)
+}
+
+// ClientIDIntrospectionAuthorizer only allows the listed clients, e.g. registered resource servers, to introspect
+// tokens.
+type ClientIDIntrospectionAuthorizer []string
+
+func (a ClientIDIntrospectionAuthorizer) AuthorizeIntrospection(_ context.Context, client Client, _ AccessRequester) error {
+	for _, id := range a {
+		if id == client.GetID() {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrRequestForbidden, "Client %s is not allowed to introspect tokens", client.GetID())
+}
+
+// AudienceIntrospectionAuthorizer only allows clients to introspect tokens whose granted audience contains the
+// client's ID or one of the client's audiences, see ClientWithAudience.
+type AudienceIntrospectionAuthorizer struct{}
+
+func (AudienceIntrospectionAuthorizer) AuthorizeIntrospection(_ context.Context, client Client, token AccessRequester) error {
+	granted := token.GetGrantedAudience()
+	if ExactAudienceMatchingStrategy(granted, client.GetID()) {
+		return nil
+	}
+
+	for _, audience := range GetClientAudience(client) {
+		if ExactAudienceMatchingStrategy(granted, audience) {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrRequestForbidden, "The token was not issued for client %s", client.GetID())
+}
+
+// OwnTokenIntrospectionAuthorizer only allows clients to introspect tokens that were issued to themselves.
+type OwnTokenIntrospectionAuthorizer struct{}
+
+func (OwnTokenIntrospectionAuthorizer) AuthorizeIntrospection(_ context.Context, client Client, token AccessRequester) error {
+	if token.GetClient() == nil || token.GetClient().GetID() != client.GetID() {
+		return errors.Wrapf(ErrRequestForbidden, "The token was not issued to client %s", client.GetID())
+	}
+	return nil
+}
+
+// AnyIntrospectionAuthorizer allows an introspection if at least one of its authorizers allows it.
+type AnyIntrospectionAuthorizer []IntrospectionAuthorizer
+
+func (a AnyIntrospectionAuthorizer) AuthorizeIntrospection(ctx context.Context, client Client, token AccessRequester) error {
+	err := errors.Wrapf(ErrRequestForbidden, "Client %s is not allowed to introspect tokens", client.GetID())
+	for _, authorizer := range a {
+		if err = authorizer.AuthorizeIntrospection(ctx, client, token); err == nil {
+			return nil
+		}
+	}
+	return err
+}
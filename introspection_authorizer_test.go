@@ -0,0 +1,86 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntrospectionAuthorizers(t *testing.T) {
+	token := NewAccessRequest(nil)
+	token.Client = &DefaultClient{ID: "owner"}
+	token.GrantAudience("https://api.example.com")
+	token.GrantAudience("resource-server")
+
+	owner := &DefaultClient{ID: "owner"}
+	resourceServer := &DefaultClient{ID: "resource-server"}
+	api := &DefaultClient{ID: "api", Audience: []string{"https://api.example.com"}}
+	other := &DefaultClient{ID: "other"}
+
+	for k, c := range []struct {
+		description string
+		authorizer  IntrospectionAuthorizer
+		client      Client
+		expectErr   error
+	}{
+		{
+			description: "should pass because the client is listed",
+			authorizer:  ClientIDIntrospectionAuthorizer{"resource-server"},
+			client:      resourceServer,
+		},
+		{
+			description: "should fail because the client is not listed",
+			authorizer:  ClientIDIntrospectionAuthorizer{"resource-server"},
+			client:      owner,
+			expectErr:   ErrRequestForbidden,
+		},
+		{
+			description: "should pass because the client id is in the token's audience",
+			authorizer:  AudienceIntrospectionAuthorizer{},
+			client:      resourceServer,
+		},
+		{
+			description: "should pass because the client's audience is in the token's audience",
+			authorizer:  AudienceIntrospectionAuthorizer{},
+			client:      api,
+		},
+		{
+			description: "should fail because the client is not in the token's audience",
+			authorizer:  AudienceIntrospectionAuthorizer{},
+			client:      owner,
+			expectErr:   ErrRequestForbidden,
+		},
+		{
+			description: "should pass because the token was issued to the client",
+			authorizer:  OwnTokenIntrospectionAuthorizer{},
+			client:      owner,
+		},
+		{
+			description: "should fail because the token was issued to another client",
+			authorizer:  OwnTokenIntrospectionAuthorizer{},
+			client:      resourceServer,
+			expectErr:   ErrRequestForbidden,
+		},
+		{
+			description: "should pass because one authorizer allows it",
+			authorizer:  AnyIntrospectionAuthorizer{OwnTokenIntrospectionAuthorizer{}, AudienceIntrospectionAuthorizer{}},
+			client:      resourceServer,
+		},
+		{
+			description: "should fail because no authorizer allows it",
+			authorizer:  AnyIntrospectionAuthorizer{OwnTokenIntrospectionAuthorizer{}, AudienceIntrospectionAuthorizer{}},
+			client:      other,
+			expectErr:   ErrRequestForbidden,
+		},
+		{
+			description: "should fail because there are no authorizers",
+			authorizer:  AnyIntrospectionAuthorizer{},
+			client:      owner,
+			expectErr:   ErrRequestForbidden,
+		},
+	} {
+		err := c.authorizer.AuthorizeIntrospection(nil, c.client, token)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+}
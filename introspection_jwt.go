@@ -0,0 +1,158 @@
+package fosite
+
+import (
+	"mime"
+	"strings"
+	"time"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+// JWTIntrospectionContentType is the media type of JWT introspection responses as defined in
+// https://tools.ietf.org/html/rfc9701#section-4
+const JWTIntrospectionContentType = "application/token-introspection+jwt"
+
+// ClientWithJWTIntrospection is an optional extension of Client for clients that registered the algorithms used
+// for JWT introspection responses, see https://tools.ietf.org/html/rfc9701#section-6
+type ClientWithJWTIntrospection interface {
+	// GetIntrospectionSignedResponseAlg returns the algorithm used to sign introspection responses, or an empty
+	// string to use the default.
+	GetIntrospectionSignedResponseAlg() string
+
+	// GetIntrospectionEncryptedResponseAlg returns the key management algorithm used to encrypt introspection
+	// responses, or an empty string if responses are only signed.
+	GetIntrospectionEncryptedResponseAlg() string
+
+	// GetIntrospectionEncryptedResponseEnc returns the content encryption algorithm used to encrypt introspection
+	// responses, or an empty string to use A128CBC-HS256.
+	GetIntrospectionEncryptedResponseEnc() string
+
+	Client
+}
+
+// DefaultClientWithJWTIntrospection is a DefaultClient that registered algorithms for JWT introspection responses.
+type DefaultClientWithJWTIntrospection struct {
+	*DefaultClient
+
+	IntrospectionSignedResponseAlg    string `json:"introspection_signed_response_alg,omitempty"`
+	IntrospectionEncryptedResponseAlg string `json:"introspection_encrypted_response_alg,omitempty"`
+	IntrospectionEncryptedResponseEnc string `json:"introspection_encrypted_response_enc,omitempty"`
+}
+
+func (c *DefaultClientWithJWTIntrospection) GetIntrospectionSignedResponseAlg() string {
+	return c.IntrospectionSignedResponseAlg
+}
+
+func (c *DefaultClientWithJWTIntrospection) GetIntrospectionEncryptedResponseAlg() string {
+	return c.IntrospectionEncryptedResponseAlg
+}
+
+func (c *DefaultClientWithJWTIntrospection) GetIntrospectionEncryptedResponseEnc() string {
+	return c.IntrospectionEncryptedResponseEnc
+}
+
+// JWTIntrospectionResponder is an optional extension of IntrospectionResponder for responses to introspection
+// requests that may have asked for a JWT response. IntrospectionResponse implements it.
+type JWTIntrospectionResponder interface {
+	// IsJWTRequested returns true if the client asked for a JWT response.
+	IsJWTRequested() bool
+
+	// GetIntrospectingClient returns the client that requested the introspection.
+	GetIntrospectingClient() Client
+
+	IntrospectionResponder
+}
+
+// JWTSigningKey is a private key used to sign JWTs, e.g. an *rsa.PrivateKey or an *ecdsa.PrivateKey.
+type JWTSigningKey struct {
+	// KeyID is added to the JWT header if set.
+	KeyID string
+
+	PrivateKey interface{}
+}
+
+// JWTIntrospectionEncrypter encrypts signed JWT introspection responses, e.g. using a JOSE library. It returns the
+// JWE compact serialization of the signed response encrypted for the client using the given algorithms.
+type JWTIntrospectionEncrypter interface {
+	Encrypt(client Client, alg, enc string, signed string) (string, error)
+}
+
+// JWTIntrospectionStrategy generates JWT introspection responses as defined in https://tools.ietf.org/html/rfc9701
+type JWTIntrospectionStrategy struct {
+	// Issuer is the issuer identifier of the authorization server.
+	Issuer string
+
+	// SigningKeys maps the supported signing algorithms (e.g. RS256, ES256) to their keys.
+	SigningKeys map[string]JWTSigningKey
+
+	// DefaultSigningAlgorithm is used for clients that did not register a signing algorithm. Defaults to RS256.
+	DefaultSigningAlgorithm string
+
+	// Encrypter encrypts the responses of clients that registered an encryption algorithm. If nil, introspection
+	// fails for such clients.
+	Encrypter JWTIntrospectionEncrypter
+}
+
+// Generate returns the JWT introspection response for the client containing the given introspection response
+// members, see https://tools.ietf.org/html/rfc9701#section-5
+func (s *JWTIntrospectionStrategy) Generate(client Client, introspection interface{}) (string, error) {
+	alg := s.DefaultSigningAlgorithm
+	if alg == "" {
+		alg = "RS256"
+	}
+
+	var encAlg, enc string
+	if jc, ok := client.(ClientWithJWTIntrospection); ok {
+		if a := jc.GetIntrospectionSignedResponseAlg(); a != "" {
+			alg = a
+		}
+		encAlg = jc.GetIntrospectionEncryptedResponseAlg()
+		enc = jc.GetIntrospectionEncryptedResponseEnc()
+		if encAlg != "" && enc == "" {
+			enc = "A128CBC-HS256"
+		}
+	}
+
+	method := jwtx.GetSigningMethod(alg)
+	key, ok := s.SigningKeys[alg]
+	if method == nil || alg == "none" || !ok {
+		return "", errors.Wrapf(ErrMisconfiguration, "Signing algorithm %s is not supported for introspection responses", alg)
+	}
+
+	token := jwtx.NewWithClaims(method, jwtx.MapClaims{
+		"iss":                 s.Issuer,
+		"aud":                 client.GetID(),
+		"iat":                 float64(time.Now().Unix()), // jwt-go does not support int64 as datatype
+		"token_introspection": introspection,
+	})
+	token.Header["typ"] = "token-introspection+jwt"
+	if key.KeyID != "" {
+		token.Header["kid"] = key.KeyID
+	}
+
+	signed, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		return "", errors.Wrap(ErrServerError, err.Error())
+	} else if encAlg == "" {
+		return signed, nil
+	} else if s.Encrypter == nil {
+		return "", errors.Wrapf(ErrMisconfiguration, "Client %s requires encrypted introspection responses but no encrypter is configured", client.GetID())
+	}
+
+	encrypted, err := s.Encrypter.Encrypt(client, encAlg, enc, signed)
+	if err != nil {
+		return "", errors.Wrap(ErrServerError, err.Error())
+	}
+	return encrypted, nil
+}
+
+// acceptsJWTIntrospection returns true if the Accept header asks for a JWT introspection response.
+func acceptsJWTIntrospection(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange)); err == nil && strings.EqualFold(mediaType, JWTIntrospectionContentType) {
+			return true
+		}
+	}
+	return false
+}
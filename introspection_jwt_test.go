@@ -0,0 +1,108 @@
+package fosite
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http/httptest"
+	"testing"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type introspectionTestEncrypter struct{}
+
+func (introspectionTestEncrypter) Encrypt(client Client, alg, enc string, signed string) (string, error) {
+	return client.GetID() + "." + alg + "." + enc + "." + signed, nil
+}
+
+func TestJWTIntrospectionStrategy_Generate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	s := &JWTIntrospectionStrategy{
+		Issuer: "https://server.example.com",
+		SigningKeys: map[string]JWTSigningKey{
+			"RS256": {KeyID: "rsa", PrivateKey: rsaKey},
+			"ES256": {PrivateKey: ecKey},
+		},
+	}
+	body := map[string]interface{}{"active": true, "scope": "foo"}
+
+	token, err := s.Generate(&DefaultClient{ID: "rs"}, body)
+	require.Nil(t, err, "%s", err)
+	parsed, err := jwtx.Parse(token, func(*jwtx.Token) (interface{}, error) { return &rsaKey.PublicKey, nil })
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "token-introspection+jwt", parsed.Header["typ"])
+	assert.Equal(t, "rsa", parsed.Header["kid"])
+	claims := parsed.Claims.(jwtx.MapClaims)
+	assert.Equal(t, "https://server.example.com", claims["iss"])
+	assert.Equal(t, "rs", claims["aud"])
+	assert.NotNil(t, claims["iat"])
+	assert.Equal(t, map[string]interface{}{"active": true, "scope": "foo"}, claims["token_introspection"])
+
+	es := &DefaultClientWithJWTIntrospection{DefaultClient: &DefaultClient{ID: "es"}, IntrospectionSignedResponseAlg: "ES256"}
+	token, err = s.Generate(es, body)
+	require.Nil(t, err, "%s", err)
+	parsed, err = jwtx.Parse(token, func(*jwtx.Token) (interface{}, error) { return &ecKey.PublicKey, nil })
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "ES256", parsed.Header["alg"])
+
+	ps := &DefaultClientWithJWTIntrospection{DefaultClient: &DefaultClient{ID: "ps"}, IntrospectionSignedResponseAlg: "PS256"}
+	_, err = s.Generate(ps, body)
+	assert.Equal(t, ErrMisconfiguration, errors.Cause(err))
+
+	none := &DefaultClientWithJWTIntrospection{DefaultClient: &DefaultClient{ID: "none"}, IntrospectionSignedResponseAlg: "none"}
+	_, err = s.Generate(none, body)
+	assert.Equal(t, ErrMisconfiguration, errors.Cause(err))
+
+	encrypted := &DefaultClientWithJWTIntrospection{DefaultClient: &DefaultClient{ID: "enc"}, IntrospectionEncryptedResponseAlg: "RSA-OAEP"}
+	_, err = s.Generate(encrypted, body)
+	assert.Equal(t, ErrMisconfiguration, errors.Cause(err))
+
+	s.Encrypter = introspectionTestEncrypter{}
+	token, err = s.Generate(encrypted, body)
+	require.Nil(t, err, "%s", err)
+	assert.Contains(t, token, "enc.RSA-OAEP.A128CBC-HS256.")
+}
+
+func TestAcceptsJWTIntrospection(t *testing.T) {
+	for k, c := range []struct {
+		accept string
+		expect bool
+	}{
+		{accept: "", expect: false},
+		{accept: "application/json", expect: false},
+		{accept: "application/token-introspection+jwt", expect: true},
+		{accept: "application/json;q=0.5, application/token-introspection+jwt", expect: true},
+		{accept: "Application/Token-Introspection+JWT; charset=utf-8", expect: true},
+	} {
+		assert.Equal(t, c.expect, acceptsJWTIntrospection(c.accept), "(%d) %s", k, c.accept)
+	}
+}
+
+func TestWriteIntrospectionResponseJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	f := &Fosite{JWTIntrospectionStrategy: &JWTIntrospectionStrategy{
+		SigningKeys: map[string]JWTSigningKey{"RS256": {PrivateKey: key}},
+	}}
+
+	rw := httptest.NewRecorder()
+	f.WriteIntrospectionResponse(rw, &IntrospectionResponse{Active: false, Client: &DefaultClient{ID: "rs"}, JWTRequested: true})
+	assert.Equal(t, JWTIntrospectionContentType, rw.Header().Get("Content-Type"))
+
+	parsed, err := jwtx.Parse(rw.Body.String(), func(*jwtx.Token) (interface{}, error) { return &key.PublicKey, nil })
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, map[string]interface{}{"active": false}, parsed.Claims.(jwtx.MapClaims)["token_introspection"])
+
+	rw = httptest.NewRecorder()
+	f.WriteIntrospectionResponse(rw, &IntrospectionResponse{Active: false, Client: &DefaultClient{ID: "rs"}})
+	assert.Equal(t, "{\"active\":false}\n", rw.Body.String())
+}
@@ -1,6 +1,7 @@
 package fosite_test
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	"github.com/ory/fosite/storage"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIntrospectionResponse(t *testing.T) {
@@ -87,6 +89,18 @@ func TestNewIntrospectionRequest(t *testing.T) {
 			},
 			isActive: true,
 		},
+		{
+			description: "should fail because the client may not introspect the token",
+			setup: func() {
+				f.IntrospectionAuthorizer = IntrospectionAuthorizerFunc(func(_ context.Context, _ Client, _ AccessRequester) error {
+					return ErrRequestForbidden
+				})
+				validator.EXPECT().IntrospectToken(nil, "some-token", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				validator.EXPECT().IntrospectToken(nil, "introspect-token", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			isActive:  false,
+			expectErr: ErrInactiveToken,
+		},
 	} {
 		c.setup()
 		res, err := f.NewIntrospectionRequest(nil, httpreq, &DefaultSession{})
@@ -97,3 +111,71 @@ func TestNewIntrospectionRequest(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestNewIntrospectionRequestJWT(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validator := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	f := compose.ComposeAllEnabled(new(compose.Config), storage.NewMemoryStore(), []byte{}, nil).(*Fosite)
+	f.TokenIntrospectionHandlers = TokenIntrospectionHandlers{validator}
+	f.JWTIntrospectionStrategy = &JWTIntrospectionStrategy{}
+	httpreq := &http.Request{
+		Method: "POST",
+		Header: http.Header{
+			"Authorization": []string{"bearer some-token"},
+			"Accept":        []string{JWTIntrospectionContentType},
+		},
+		PostForm: url.Values{
+			"token": []string{"introspect-token"},
+		},
+	}
+
+	validator.EXPECT().IntrospectToken(nil, "some-token", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	validator.EXPECT().IntrospectToken(nil, "introspect-token", gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("asdf"))
+	res, err := f.NewIntrospectionRequest(nil, httpreq, &DefaultSession{})
+	assert.Nil(t, err, "%s", err)
+	assert.False(t, res.IsActive())
+	assert.True(t, res.(JWTIntrospectionResponder).IsJWTRequested())
+
+	validator.EXPECT().IntrospectToken(nil, "some-token", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	validator.EXPECT().IntrospectToken(nil, "introspect-token", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	res, err = f.NewIntrospectionRequest(nil, httpreq, &DefaultSession{})
+	assert.Nil(t, err, "%s", err)
+	assert.True(t, res.IsActive())
+	assert.True(t, res.(JWTIntrospectionResponder).IsJWTRequested())
+	assert.NotNil(t, res.(JWTIntrospectionResponder).GetIntrospectingClient())
+}
+
+func TestNewIntrospectionRequestPairwiseSubject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validator := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	f := compose.ComposeAllEnabled(new(compose.Config), storage.NewMemoryStore(), []byte{}, nil).(*Fosite)
+	f.TokenIntrospectionHandlers = TokenIntrospectionHandlers{validator}
+	f.SubjectIdentifierStrategy = &PairwiseSubjectIdentifierStrategy{Salt: []byte("salt")}
+
+	tokenClient := &DefaultClient{ID: "foo", SubjectType: SubjectTypePairwise, RedirectURIs: []string{"https://foo.example.com/cb"}}
+	httpreq := &http.Request{
+		Method: "POST",
+		Header: http.Header{
+			"Authorization": []string{"bearer some-token"},
+		},
+		PostForm: url.Values{
+			"token": []string{"introspect-token"},
+		},
+	}
+	validator.EXPECT().IntrospectToken(nil, "some-token", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	validator.EXPECT().IntrospectToken(nil, "introspect-token", gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ string, _ TokenType, ar AccessRequester, _ []string) {
+		ar.(*AccessRequest).Client = tokenClient
+	}).Return(nil)
+
+	res, err := f.NewIntrospectionRequest(nil, httpreq, &DefaultSession{Subject: "peter"})
+	require.Nil(t, err, "%s", err)
+
+	expected, err := f.SubjectIdentifierStrategy.GetSubjectIdentifier(nil, tokenClient, "peter")
+	require.Nil(t, err)
+	assert.NotEqual(t, "peter", expected)
+	assert.Equal(t, expected, res.(IntrospectionSubjectResponder).GetSubject())
+}
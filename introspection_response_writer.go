@@ -114,6 +114,17 @@ func (f *Fosite) WriteIntrospectionError(rw http.ResponseWriter, err error) {
 // OPTIONAL.  String identifier for the token, as defined in JWT
 // [RFC7519].
 //
+// In addition, the granted authorization details are returned in the "authorization_details" member as defined in
+// https://tools.ietf.org/html/rfc9396#section-9.2
+//
+// Tokens bound to a DPoP key additionally contain the key's thumbprint in the "cnf" member as defined in
+// https://tools.ietf.org/html/rfc9449#section-6.2
+//
+// The "exp" member is the expiry of the introspected token's type, and "token_type" is only returned for access
+// tokens. "jti" is only returned for tokens carrying their own identifier, see TokenIDSession, because the access and
+// refresh tokens of a request share its ID. Sessions
+// implementing IntrospectionClaimsSession may add custom top-level members, except for the reserved members above.
+//
 // Specific implementations MAY extend this structure with their own
 // service-specific response names as top-level members of this JSON
 // object.  Response names intended to be used across domains MUST be
@@ -173,31 +184,111 @@ func (f *Fosite) WriteIntrospectionError(rw http.ResponseWriter, err error) {
 //	 {
 //	   "active": false
 //	 }
+//
+// If the client asked for a JWT response, the response members are instead written as the token_introspection
+// claim of a signed (and optionally encrypted) JWT as defined in https://tools.ietf.org/html/rfc9701#section-5
 func (f *Fosite) WriteIntrospectionResponse(rw http.ResponseWriter, r IntrospectionResponder) {
+	if jr, ok := r.(JWTIntrospectionResponder); ok && jr.IsJWTRequested() && f.JWTIntrospectionStrategy != nil {
+		token, err := f.JWTIntrospectionStrategy.Generate(jr.GetIntrospectingClient(), introspectionResponseBody(r, f.Issuer))
+		if err != nil {
+			writeJsonError(rw, err)
+			return
+		}
+
+		rw.Header().Set("Content-Type", JWTIntrospectionContentType)
+		_, _ = rw.Write([]byte(token))
+		return
+	}
+
+	_ = json.NewEncoder(rw).Encode(introspectionResponseBody(r, f.Issuer))
+}
+
+// introspectionReservedMembers are the members defined by RFC 7662, RFC 9449 and RFC 9396 which custom members of
+// an IntrospectionClaimsSession can not set, even if the standard member is absent.
+var introspectionReservedMembers = []string{
+	"active", "scope", "client_id", "username", "token_type", "exp", "iat", "nbf", "sub", "aud", "iss", "jti",
+	"cnf", "authorization_details",
+}
+
+// introspectionResponseBody returns the members of the introspection response.
+func introspectionResponseBody(r IntrospectionResponder, issuer string) interface{} {
 	if !r.IsActive() {
-		_ = json.NewEncoder(rw).Encode(&struct {
+		return &struct {
 			Active bool `json:"active"`
-		}{Active: false})
+		}{Active: false}
+	}
+
+	ar := r.GetAccessRequester()
+	tokenType := GetIntrospectedTokenType(ar)
+	body := map[string]interface{}{}
+	if session, ok := ar.GetSession().(IntrospectionClaimsSession); ok {
+		for k, v := range session.GetIntrospectionClaims() {
+			if !StringInSlice(k, introspectionReservedMembers) {
+				body[k] = v
+			}
+		}
+	}
+
+	body["active"] = true
+	body["iat"] = ar.GetRequestedAt().Unix()
+	body["nbf"] = ar.GetRequestedAt().Unix()
+	setIntrospectionMember(body, "scope", strings.Join(ar.GetGrantedScopes(), " "))
+	setIntrospectionMember(body, "iss", issuer)
+	if client := ar.GetClient(); client != nil {
+		setIntrospectionMember(body, "client_id", client.GetID())
+	}
+	if tokenType == AccessToken {
+		body["token_type"] = getIntrospectionTokenType(ar)
+	}
+	if audience := ar.GetGrantedAudience(); len(audience) > 0 {
+		body["aud"] = []string(audience)
+	}
+	if details := ar.GetGrantedAuthorizationDetails(); len(details) > 0 {
+		body["authorization_details"] = details
+	}
+	if cnf := dpopConfirmation(ar); cnf != nil {
+		body["cnf"] = cnf
+	}
+
+	if session, ok := ar.GetSession().(TokenIDSession); ok {
+		setIntrospectionMember(body, "jti", session.GetTokenID())
+	}
+
+	if session := ar.GetSession(); session != nil {
+		if exp := session.GetExpiresAt(tokenType); !exp.IsZero() {
+			body["exp"] = exp.Unix()
+		}
+		setIntrospectionMember(body, "sub", session.GetSubject())
+		if sr, ok := r.(IntrospectionSubjectResponder); ok && sr.GetSubject() != "" {
+			body["sub"] = sr.GetSubject()
+		}
+		setIntrospectionMember(body, "username", session.GetUsername())
+	}
+
+	return body
+}
+
+// setIntrospectionMember sets a string member, or removes it if the value is empty.
+func setIntrospectionMember(body map[string]interface{}, key, value string) {
+	if value == "" {
+		delete(body, key)
 		return
 	}
+	body[key] = value
+}
 
-	_ = json.NewEncoder(rw).Encode(struct {
-		Active    bool    `json:"active"`
-		ClientID  string  `json:"client_id,omitempty"`
-		Scope     string  `json:"scope,omitempty"`
-		ExpiresAt int64   `json:"exp,omitempty"`
-		IssuedAt  int64   `json:"iat,omitempty"`
-		Subject   string  `json:"sub,omitempty"`
-		Username  string  `json:"username,omitempty"`
-		Session   Session `json:"sess,omitempty"`
-	}{
-		Active:    true,
-		ClientID:  r.GetAccessRequester().GetClient().GetID(),
-		Scope:     strings.Join(r.GetAccessRequester().GetGrantedScopes(), " "),
-		ExpiresAt: r.GetAccessRequester().GetSession().GetExpiresAt(AccessToken).Unix(),
-		IssuedAt:  r.GetAccessRequester().GetRequestedAt().Unix(),
-		Subject:   r.GetAccessRequester().GetSession().GetSubject(),
-		Username:  r.GetAccessRequester().GetSession().GetUsername(),
-		// Session:   r.GetAccessRequester().GetSession(),
-	})
+// getIntrospectionTokenType returns the token type of an access token as defined in
+// https://tools.ietf.org/html/rfc6749#section-7.1
+func getIntrospectionTokenType(r Requester) string {
+	if GetDPoPJWKThumbprint(r) != "" {
+		return "DPoP"
+	}
+	return "bearer"
+}
+
+func dpopConfirmation(r Requester) map[string]string {
+	if thumbprint := GetDPoPJWKThumbprint(r); thumbprint != "" {
+		return map[string]string{"jkt": thumbprint}
+	}
+	return nil
 }
@@ -1,13 +1,18 @@
 package fosite_test
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/ory/fosite"
 	"github.com/ory/fosite/internal"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWriteIntrospectionError(t *testing.T) {
@@ -39,3 +44,109 @@ func TestWriteIntrospectionResponse(t *testing.T) {
 		AccessRequester: NewAccessRequest(nil),
 	})
 }
+
+func TestWriteIntrospectionResponseMembers(t *testing.T) {
+	f := &Fosite{Issuer: "https://server.example.com"}
+	requestedAt := time.Now().UTC().Round(time.Second)
+
+	newRequester := func(tokenType TokenType) *AccessRequest {
+		ar := NewAccessRequest(&DefaultSession{
+			Subject:  "peter",
+			Username: "peter@example.com",
+			ExpiresAt: map[TokenType]time.Time{
+				AccessToken:  requestedAt.Add(time.Hour),
+				RefreshToken: requestedAt.Add(time.Hour * 24),
+			},
+			Extra: map[string]interface{}{
+				"tenant":                "acme",
+				"sub":                   "not-peter",
+				"iss":                   "not-the-server",
+				"jti":                   "forged",
+				"token_type":            "forged",
+				"cnf":                   map[string]interface{}{"jkt": "forged"},
+				"authorization_details": []interface{}{"forged"},
+			},
+		})
+		ar.RequestedAt = requestedAt
+		ar.Client = &DefaultClient{ID: "foo"}
+		ar.GrantScope("read")
+		ar.GrantScope("write")
+		ar.GrantAudience("https://api.example.com")
+		ar.SetIntrospectedTokenType(tokenType)
+		return ar
+	}
+
+	for k, c := range []struct {
+		description string
+		requester   *AccessRequest
+		expect      map[string]interface{}
+	}{
+		{
+			description: "should return all members of an access token",
+			requester:   newRequester(AccessToken),
+			expect: map[string]interface{}{
+				"active":     true,
+				"client_id":  "foo",
+				"scope":      "read write",
+				"sub":        "peter",
+				"username":   "peter@example.com",
+				"token_type": "bearer",
+				"aud":        []interface{}{"https://api.example.com"},
+				"iss":        "https://server.example.com",
+				"iat":        float64(requestedAt.Unix()),
+				"nbf":        float64(requestedAt.Unix()),
+				"exp":        float64(requestedAt.Add(time.Hour).Unix()),
+				"tenant":     "acme",
+			},
+		},
+		{
+			description: "should return the refresh token expiry and no token type",
+			requester:   newRequester(RefreshToken),
+			expect: map[string]interface{}{
+				"active":    true,
+				"client_id": "foo",
+				"scope":     "read write",
+				"sub":       "peter",
+				"username":  "peter@example.com",
+				"aud":       []interface{}{"https://api.example.com"},
+				"iss":       "https://server.example.com",
+				"iat":       float64(requestedAt.Unix()),
+				"nbf":       float64(requestedAt.Unix()),
+				"exp":       float64(requestedAt.Add(time.Hour * 24).Unix()),
+				"tenant":    "acme",
+			},
+		},
+	} {
+		rw := httptest.NewRecorder()
+		f.WriteIntrospectionResponse(rw, &IntrospectionResponse{Active: true, AccessRequester: c.requester})
+
+		var body map[string]interface{}
+		require.Nil(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		assert.Equal(t, c.expect, body, "(%d) %s", k, c.description)
+	}
+
+	rw := httptest.NewRecorder()
+	f.WriteIntrospectionResponse(rw, &IntrospectionResponse{Active: true, AccessRequester: newRequester(AccessToken), Subject: "pairwise-peter"})
+
+	var body map[string]interface{}
+	require.Nil(t, json.Unmarshal(rw.Body.Bytes(), &body))
+	assert.Equal(t, "pairwise-peter", body["sub"])
+
+	ar := newRequester(AccessToken)
+	ar.Session = &tokenIDSession{DefaultSession: ar.Session.(*DefaultSession), id: "token-id"}
+	rw = httptest.NewRecorder()
+	f.WriteIntrospectionResponse(rw, &IntrospectionResponse{Active: true, AccessRequester: ar})
+
+	body = map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(rw.Body.Bytes(), &body))
+	assert.Equal(t, "token-id", body["jti"])
+}
+
+type tokenIDSession struct {
+	*DefaultSession
+	id string
+}
+
+func (s *tokenIDSession) GetTokenID() string {
+	return s.id
+}
@@ -123,8 +123,21 @@ type OAuth2Provider interface {
 	// https://tools.ietf.org/html/rfc7009#section-2.2
 	WriteRevocationResponse(rw http.ResponseWriter, err error)
 
+	// RevokeTokensBySubject revokes all access tokens, refresh tokens, authorization codes and OpenID Connect
+	// sessions issued for the subject, e.g. after the resource owner reset their password.
+	RevokeTokensBySubject(ctx context.Context, subject string) error
+
+	// RevokeTokensByClient revokes all access tokens, refresh tokens, authorization codes and OpenID Connect
+	// sessions issued to the client, e.g. when the client is deleted.
+	RevokeTokensByClient(ctx context.Context, clientID string) error
+
+	// RevokeTokensBySubjectAndClient revokes all access tokens, refresh tokens, authorization codes and OpenID
+	// Connect sessions issued for the subject to the client, e.g. when the resource owner revokes their consent.
+	RevokeTokensBySubjectAndClient(ctx context.Context, subject, clientID string) error
+
 	// IntrospectToken returns token metadata, if the token is valid. Tokens generated by the authorization endpoint,
-	// such as the authorization code, can not be introspected.
+	// such as the authorization code, can not be introspected. If the context was created by
+	// NewIntrospectionAudienceContext, the token must have been granted that audience.
 	IntrospectToken(ctx context.Context, token string, tokenType TokenType, session Session, scope ...string) (AccessRequester, error)
 
 	// NewIntrospectionRequest initiates token introspection as defined in
@@ -177,6 +190,30 @@ type Requester interface {
 	// GrantScope marks a request's scope as granted.
 	GrantScope(scope string)
 
+	// GetRequestedAudience returns the requested audiences, e.g. resource servers the token is meant for.
+	GetRequestedAudience() (audience Arguments)
+
+	// SetRequestedAudience sets the requested audiences.
+	SetRequestedAudience(audience Arguments)
+
+	// GetGrantedAudience returns all granted audiences.
+	GetGrantedAudience() (grantedAudience Arguments)
+
+	// GrantAudience marks a request's audience as granted.
+	GrantAudience(audience string)
+
+	// GetRequestedAuthorizationDetails returns the requested authorization details.
+	GetRequestedAuthorizationDetails() (details AuthorizationDetails)
+
+	// SetRequestedAuthorizationDetails sets the requested authorization details.
+	SetRequestedAuthorizationDetails(details AuthorizationDetails)
+
+	// GetGrantedAuthorizationDetails returns all granted authorization details.
+	GetGrantedAuthorizationDetails() (grantedDetails AuthorizationDetails)
+
+	// GrantAuthorizationDetail marks a requested authorization details object as granted.
+	GrantAuthorizationDetail(detail AuthorizationDetail)
+
 	// GetSession returns a pointer to the request's session or nil if none is set.
 	GetSession() (session Session)
 
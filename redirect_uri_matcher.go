@@ -0,0 +1,167 @@
+package fosite
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RedirectURIMatcher returns true if the requested redirect URI (needle) matches one of the client's registered
+// redirect URIs (haystack). The matched redirect URI is still validated using IsValidRedirectURI.
+type RedirectURIMatcher func(haystack []string, needle string) bool
+
+const (
+	// RedirectURIMatcherExact selects ExactRedirectURIMatcher.
+	RedirectURIMatcherExact = "exact"
+
+	// RedirectURIMatcherLoopback selects LoopbackRedirectURIMatcher.
+	RedirectURIMatcherLoopback = "loopback"
+
+	// RedirectURIMatcherWildcard selects WildcardRedirectURIMatcher.
+	RedirectURIMatcherWildcard = "wildcard"
+)
+
+// ClientWithRedirectURIMatcher is an optional extension of Client for clients selecting how their redirect URIs are
+// matched, e.g. RedirectURIMatcherWildcard for preview environments.
+type ClientWithRedirectURIMatcher interface {
+	// GetRedirectURIMatcher returns the name of the matcher, or an empty string to use the default matcher.
+	GetRedirectURIMatcher() string
+
+	Client
+}
+
+// ExactRedirectURIMatcher only matches redirect URIs that are registered verbatim (simple string comparison as
+// defined in https://tools.ietf.org/html/rfc3986#section-6.2.1).
+func ExactRedirectURIMatcher(haystack []string, needle string) bool {
+	return StringInSlice(needle, haystack)
+}
+
+// LoopbackRedirectURIMatcher matches redirect URIs that are registered verbatim and loopback IP redirect URIs with
+// any port, see https://tools.ietf.org/html/rfc8252#section-7.3
+//
+//   The authorization server MUST allow any port to be specified at the
+//   time of the request for loopback IP redirect URIs, to accommodate
+//   clients that obtain an available ephemeral port from the operating
+//   system at the time of the request.
+//
+// It is the default matcher.
+func LoopbackRedirectURIMatcher(haystack []string, needle string) bool {
+	if ExactRedirectURIMatcher(haystack, needle) {
+		return true
+	}
+
+	requested, err := url.Parse(needle)
+	if err != nil || !isLoopbackRedirectURI(requested) {
+		return false
+	}
+
+	for _, this := range haystack {
+		registered, err := url.Parse(this)
+		if err != nil || !isLoopbackRedirectURI(registered) {
+			continue
+		} else if registered.Hostname() == requested.Hostname() && registered.Path == requested.Path && registered.RawQuery == requested.RawQuery {
+			return true
+		}
+	}
+	return false
+}
+
+// WildcardRedirectURIMatcher matches redirect URIs that are registered verbatim and redirect URIs matching a
+// registered pattern whose leftmost host label is a wildcard, e.g. https://*.preview.example.com/callback matches
+// https://pr-123.preview.example.com/callback. The wildcard is constrained:
+//
+// * both URIs must use https and the pattern must have at least two labels after the wildcard, so *.com is ignored,
+// * the wildcard matches exactly one non-empty label of letters, digits and hyphens,
+// * port, path and query must be identical.
+func WildcardRedirectURIMatcher(haystack []string, needle string) bool {
+	if ExactRedirectURIMatcher(haystack, needle) {
+		return true
+	}
+
+	requested, err := url.Parse(needle)
+	if err != nil || requested.Scheme != "https" || requested.User != nil {
+		return false
+	}
+
+	host := strings.ToLower(requested.Hostname())
+	dot := strings.Index(host, ".")
+	if dot < 1 || !isHostLabel(host[:dot]) {
+		return false
+	}
+
+	for _, this := range haystack {
+		pattern, err := url.Parse(this)
+		if err != nil || pattern.Scheme != "https" || pattern.User != nil {
+			continue
+		}
+
+		suffix := strings.ToLower(pattern.Hostname())
+		if !strings.HasPrefix(suffix, "*.") || strings.Count(suffix, ".") < 2 || strings.Contains(suffix[1:], "*") {
+			continue
+		} else if host[dot:] != suffix[1:] {
+			continue
+		} else if pattern.Port() == requested.Port() && pattern.Path == requested.Path && pattern.RawQuery == requested.RawQuery {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRedirectURIMatcher returns the matcher selected by a client implementing ClientWithRedirectURIMatcher. Names are
+// looked up in matchers first and then in the built-in matchers. Clients not selecting a matcher use fallback, or
+// LoopbackRedirectURIMatcher if fallback is nil.
+func GetRedirectURIMatcher(client Client, fallback RedirectURIMatcher, matchers map[string]RedirectURIMatcher) (RedirectURIMatcher, error) {
+	var name string
+	if mc, ok := client.(ClientWithRedirectURIMatcher); ok {
+		name = mc.GetRedirectURIMatcher()
+	}
+
+	if matcher, ok := matchers[name]; ok && name != "" {
+		return matcher, nil
+	}
+
+	switch name {
+	case "":
+		if fallback != nil {
+			return fallback, nil
+		}
+		return LoopbackRedirectURIMatcher, nil
+	case RedirectURIMatcherExact:
+		return ExactRedirectURIMatcher, nil
+	case RedirectURIMatcherLoopback:
+		return LoopbackRedirectURIMatcher, nil
+	case RedirectURIMatcherWildcard:
+		return WildcardRedirectURIMatcher, nil
+	}
+	return nil, errors.Wrapf(ErrServerError, "Redirect URI matcher %s of client %s is unknown", name, client.GetID())
+}
+
+// GetRedirectURIMatcher returns the redirect URI matcher of the client, see Fosite.RedirectURIMatcher.
+func (f *Fosite) GetRedirectURIMatcher(client Client) (RedirectURIMatcher, error) {
+	return GetRedirectURIMatcher(client, f.RedirectURIMatcher, f.RedirectURIMatchers)
+}
+
+// isLoopbackRedirectURI returns true if the redirect URI uses http and a loopback IP literal such as 127.0.0.1 or
+// [::1] as described in https://tools.ietf.org/html/rfc8252#section-7.3 - the "localhost" host name is not matched
+// with a flexible port, because it may resolve to a non-loopback interface.
+func isLoopbackRedirectURI(redirectURI *url.URL) bool {
+	if redirectURI.Scheme != "http" {
+		return false
+	}
+	ip := net.ParseIP(redirectURI.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
+
+func isHostLabel(label string) bool {
+	if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectURIMatchers(t *testing.T) {
+	for k, c := range []struct {
+		haystack []string
+		needle   string
+		exact    bool
+		loopback bool
+		wildcard bool
+	}{
+		{haystack: []string{"https://foo.com/cb"}, needle: "https://foo.com/cb", exact: true, loopback: true, wildcard: true},
+		{haystack: []string{"https://foo.com/cb"}, needle: "https://foo.com/cb2"},
+		{haystack: []string{"http://127.0.0.1/cb"}, needle: "http://127.0.0.1:51004/cb", loopback: true},
+		{haystack: []string{"http://[::1]:8080/cb"}, needle: "http://[::1]:51004/cb", loopback: true},
+		{haystack: []string{"http://localhost/cb"}, needle: "http://localhost:51004/cb"},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "https://pr-123.preview.example.com/cb", wildcard: true},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "https://PR-123.preview.example.com/cb", wildcard: true},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "https://a.b.preview.example.com/cb"},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "https://preview.example.com/cb"},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "https://pr-1.preview.example.com/other"},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "https://pr-1.preview.example.com:8443/cb"},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "http://pr-1.preview.example.com/cb"},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "https://pr-1.evilpreview.example.com/cb"},
+		{haystack: []string{"https://*.preview.example.com/cb"}, needle: "https://user@pr-1.preview.example.com/cb"},
+		{haystack: []string{"https://*.com/cb"}, needle: "https://example.com/cb"},
+		{haystack: []string{"https://foo.*.example.com/cb"}, needle: "https://foo.bar.example.com/cb"},
+	} {
+		assert.Equal(t, c.exact, ExactRedirectURIMatcher(c.haystack, c.needle), "exact %d", k)
+		assert.Equal(t, c.loopback, LoopbackRedirectURIMatcher(c.haystack, c.needle), "loopback %d", k)
+		assert.Equal(t, c.wildcard, WildcardRedirectURIMatcher(c.haystack, c.needle), "wildcard %d", k)
+	}
+}
+
+func TestGetRedirectURIMatcher(t *testing.T) {
+	custom := func(haystack []string, needle string) bool { return needle == "custom:/cb" }
+	f := &Fosite{RedirectURIMatchers: map[string]RedirectURIMatcher{"custom": custom}}
+
+	for k, c := range []struct {
+		client    Client
+		rawurl    string
+		expectErr error
+	}{
+		{client: &DefaultClient{RedirectURIs: []string{"http://127.0.0.1/cb"}}, rawurl: "http://127.0.0.1:1234/cb"},
+		{client: &DefaultClient{RedirectURIs: []string{"http://127.0.0.1/cb"}, RedirectURIMatcher: RedirectURIMatcherExact}, rawurl: "http://127.0.0.1:1234/cb", expectErr: ErrInvalidRequest},
+		{client: &DefaultClient{RedirectURIs: []string{"https://*.example.com/cb"}, RedirectURIMatcher: RedirectURIMatcherWildcard}, rawurl: "https://foo.example.com/cb"},
+		{client: &DefaultClient{RedirectURIs: []string{"https://*.example.com/cb"}}, rawurl: "https://foo.example.com/cb", expectErr: ErrInvalidRequest},
+		{client: &DefaultClient{RedirectURIMatcher: "custom"}, rawurl: "custom:/cb"},
+		{client: &DefaultClient{RedirectURIMatcher: "unknown"}, rawurl: "https://foo.example.com/cb", expectErr: ErrServerError},
+	} {
+		matcher, err := f.GetRedirectURIMatcher(c.client)
+		if err == nil {
+			_, err = MatchRedirectURI(c.rawurl, c.client, matcher)
+		}
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s", k, err)
+	}
+
+	f.RedirectURIMatcher = ExactRedirectURIMatcher
+	matcher, err := f.GetRedirectURIMatcher(&DefaultClient{})
+	require.Nil(t, err)
+	assert.False(t, matcher([]string{"http://127.0.0.1/cb"}, "http://127.0.0.1:1234/cb"))
+}
@@ -16,15 +16,33 @@ type Request struct {
 	GrantedScopes Arguments  `json:"grantedScopes" gorethink:"grantedScopes"`
 	Form          url.Values `json:"form" gorethink:"form"`
 	Session       Session    `json:"session" gorethink:"session"`
+
+	RequestedAudience Arguments `json:"requestedAudience" gorethink:"requestedAudience"`
+	GrantedAudience   Arguments `json:"grantedAudience" gorethink:"grantedAudience"`
+
+	RequestedAuthorizationDetails AuthorizationDetails `json:"requestedAuthorizationDetails" gorethink:"requestedAuthorizationDetails"`
+	GrantedAuthorizationDetails   AuthorizationDetails `json:"grantedAuthorizationDetails" gorethink:"grantedAuthorizationDetails"`
+
+	// DPoPJWKThumbprint is the thumbprint of the DPoP key the request's tokens are bound to.
+	DPoPJWKThumbprint string `json:"dpopJwkThumbprint" gorethink:"dpopJwkThumbprint"`
+
+	// GrantScopes are the scopes granted by the resource owner for the whole grant if the request's tokens were
+	// issued for a subset of them.
+	GrantScopes Arguments `json:"grantScopes" gorethink:"grantScopes"`
 }
 
 func NewRequest() *Request {
 	return &Request{
-		Client:        &DefaultClient{},
-		Scopes:        Arguments{},
-		GrantedScopes: Arguments{},
-		Form:          url.Values{},
-		RequestedAt:   time.Now(),
+		Client:            &DefaultClient{},
+		Scopes:            Arguments{},
+		GrantedScopes:     Arguments{},
+		RequestedAudience: Arguments{},
+		GrantedAudience:   Arguments{},
+		Form:              url.Values{},
+		RequestedAt:       time.Now(),
+
+		RequestedAuthorizationDetails: AuthorizationDetails{},
+		GrantedAuthorizationDetails:   AuthorizationDetails{},
 	}
 }
 
@@ -80,6 +98,60 @@ func (a *Request) GrantScope(scope string) {
 	a.GrantedScopes = append(a.GrantedScopes, scope)
 }
 
+func (a *Request) GetRequestedAudience() Arguments {
+	return a.RequestedAudience
+}
+
+func (a *Request) SetRequestedAudience(audience Arguments) {
+	a.RequestedAudience = nil
+	for _, aud := range audience {
+		a.RequestedAudience = appendUnique(a.RequestedAudience, aud)
+	}
+}
+
+func (a *Request) GetGrantedAudience() Arguments {
+	return a.GrantedAudience
+}
+
+func (a *Request) GrantAudience(audience string) {
+	a.GrantedAudience = appendUnique(a.GrantedAudience, audience)
+}
+
+func (a *Request) GetRequestedAuthorizationDetails() AuthorizationDetails {
+	return a.RequestedAuthorizationDetails
+}
+
+func (a *Request) SetRequestedAuthorizationDetails(details AuthorizationDetails) {
+	a.RequestedAuthorizationDetails = details
+}
+
+func (a *Request) GetGrantedAuthorizationDetails() AuthorizationDetails {
+	return a.GrantedAuthorizationDetails
+}
+
+func (a *Request) GrantAuthorizationDetail(detail AuthorizationDetail) {
+	if a.GrantedAuthorizationDetails.Has(detail) {
+		return
+	}
+	a.GrantedAuthorizationDetails = append(a.GrantedAuthorizationDetails, detail)
+}
+
+func (a *Request) GetDPoPJWKThumbprint() string {
+	return a.DPoPJWKThumbprint
+}
+
+func (a *Request) SetDPoPJWKThumbprint(thumbprint string) {
+	a.DPoPJWKThumbprint = thumbprint
+}
+
+func (a *Request) GetGrantScopes() Arguments {
+	return a.GrantScopes
+}
+
+func (a *Request) SetGrantScopes(scopes Arguments) {
+	a.GrantScopes = scopes
+}
+
 func (a *Request) SetSession(session Session) {
 	a.Session = session
 }
@@ -95,6 +167,28 @@ func (a *Request) Merge(request Requester) {
 	for _, scope := range request.GetGrantedScopes() {
 		a.GrantScope(scope)
 	}
+	for _, audience := range request.GetRequestedAudience() {
+		a.RequestedAudience = appendUnique(a.RequestedAudience, audience)
+	}
+	for _, audience := range request.GetGrantedAudience() {
+		a.GrantAudience(audience)
+	}
+	for _, detail := range request.GetRequestedAuthorizationDetails() {
+		if !a.RequestedAuthorizationDetails.Has(detail) {
+			a.RequestedAuthorizationDetails = append(a.RequestedAuthorizationDetails, detail)
+		}
+	}
+	for _, detail := range request.GetGrantedAuthorizationDetails() {
+		a.GrantAuthorizationDetail(detail)
+	}
+	if thumbprint := GetDPoPJWKThumbprint(request); thumbprint != "" {
+		a.DPoPJWKThumbprint = thumbprint
+	}
+	if gr, ok := request.(GrantScopesRequester); ok {
+		for _, scope := range gr.GetGrantScopes() {
+			a.GrantScopes = appendUnique(a.GrantScopes, scope)
+		}
+	}
 	a.RequestedAt = request.GetRequestedAt()
 	a.Client = request.GetClient()
 	a.Session = request.GetSession()
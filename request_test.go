@@ -33,6 +33,7 @@ func TestMergeRequest(t *testing.T) {
 		Client:        &DefaultClient{ID: "123"},
 		Scopes:        Arguments{"asdff"},
 		GrantedScopes: []string{"asdf"},
+		GrantScopes:   Arguments{"asdf", "offline"},
 		Form:          url.Values{"foo": []string{"fasdf"}},
 		Session:       new(DefaultSession),
 	}
@@ -50,6 +51,15 @@ func TestMergeRequest(t *testing.T) {
 	assert.EqualValues(t, a.Client, b.Client)
 	assert.EqualValues(t, a.Scopes, b.Scopes)
 	assert.EqualValues(t, a.GrantedScopes, b.GrantedScopes)
+	assert.EqualValues(t, a.GrantScopes, b.GrantScopes)
 	assert.EqualValues(t, a.Form, b.Form)
 	assert.EqualValues(t, a.Session, b.Session)
 }
+
+func TestGetGrantScopes(t *testing.T) {
+	r := &Request{GrantedScopes: Arguments{"foo"}}
+	assert.Equal(t, Arguments{"foo"}, GetGrantScopes(r))
+
+	r.SetGrantScopes(Arguments{"foo", "bar"})
+	assert.Equal(t, Arguments{"foo", "bar"}, GetGrantScopes(r))
+}
@@ -24,9 +24,11 @@ import (
 // The invalidation takes place immediately, and the token cannot be
 // used again after the revocation.
 //
-// * https://tools.ietf.org/html/rfc7009#section-2.2
-// An invalid token type hint value is ignored by the authorization
-// server and does not influence the revocation response.
+// * https://tools.ietf.org/html/rfc7009#section-2.2.1
+// unsupported_token_type:  The authorization server does not support
+// the revocation of the presented token type.  That is, the
+// client tried to revoke an access token on a server not
+// supporting this feature.
 func (f *Fosite) NewRevocationRequest(ctx context.Context, r *http.Request) error {
 	if r.Method != "POST" {
 		return errors.Wrap(ErrInvalidRequest, "HTTP method is not POST")
@@ -46,7 +48,9 @@ func (f *Fosite) NewRevocationRequest(ctx context.Context, r *http.Request) erro
 
 	// Enforce client authentication for confidential clients
 	if !client.IsPublic() {
-		if err := f.Hasher.Compare(client.GetHashedSecret(), []byte(clientSecret)); err != nil {
+		if err := checkClientSecretBasic(client); err != nil {
+			return err
+		} else if err := CompareClientSecret(f.Hasher, client, []byte(clientSecret)); err != nil {
 			return errors.Wrap(ErrInvalidClient, err.Error())
 		}
 	}
@@ -56,7 +60,7 @@ func (f *Fosite) NewRevocationRequest(ctx context.Context, r *http.Request) erro
 
 	var found bool
 	for _, loader := range f.RevocationHandlers {
-		if err := loader.RevokeToken(ctx, token, tokenTypeHint); err == nil {
+		if err := loader.RevokeToken(ctx, token, tokenTypeHint, client); err == nil {
 			found = true
 		} else if errors.Cause(err) == ErrUnknownRequest {
 			// do nothing
@@ -85,9 +89,7 @@ func (f *Fosite) NewRevocationRequest(ctx context.Context, r *http.Request) erro
 // is already achieved.
 func (f *Fosite) WriteRevocationResponse(rw http.ResponseWriter, err error) {
 	switch errors.Cause(err) {
-	case ErrInvalidRequest:
-		fallthrough
-	case ErrInvalidClient:
+	case ErrInvalidRequest, ErrInvalidClient, ErrUnauthorizedClient, ErrUnsupportedTokenType, ErrServerError:
 		rw.Header().Set("Content-Type", "application/json;charset=UTF-8")
 
 		rfcerr := ErrorToRFC6749Error(err)
@@ -94,7 +94,7 @@ func TestNewRevocationRequest(t *testing.T) {
 				client.EXPECT().GetHashedSecret().Return([]byte("foo"))
 				client.EXPECT().IsPublic().Return(false)
 				hasher.EXPECT().Compare(gomock.Eq([]byte("foo")), gomock.Eq([]byte("bar"))).Return(nil)
-				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			handlers: RevocationHandlers{handler},
 		},
@@ -113,7 +113,7 @@ func TestNewRevocationRequest(t *testing.T) {
 				client.EXPECT().GetHashedSecret().Return([]byte("foo"))
 				client.EXPECT().IsPublic().Return(false)
 				hasher.EXPECT().Compare(gomock.Eq([]byte("foo")), gomock.Eq([]byte("bar"))).Return(nil)
-				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			handlers: RevocationHandlers{handler},
 		},
@@ -131,7 +131,7 @@ func TestNewRevocationRequest(t *testing.T) {
 				store.EXPECT().GetClient(gomock.Any(), gomock.Eq("foo")).Return(client, nil)
 				client.EXPECT().IsPublic().Return(true)
 				hasher.EXPECT().Compare(gomock.Eq([]byte("foo")), gomock.Eq([]byte("bar"))).Return(nil)
-				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			handlers: RevocationHandlers{handler},
 		},
@@ -149,7 +149,7 @@ func TestNewRevocationRequest(t *testing.T) {
 				store.EXPECT().GetClient(gomock.Any(), gomock.Eq("foo")).Return(client, nil)
 				client.EXPECT().GetHashedSecret().Return([]byte("foo"))
 				client.EXPECT().IsPublic().Return(false)
-				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			handlers: RevocationHandlers{handler},
 		},
@@ -168,7 +168,26 @@ func TestNewRevocationRequest(t *testing.T) {
 				client.EXPECT().GetHashedSecret().Return([]byte("foo"))
 				client.EXPECT().IsPublic().Return(false)
 				hasher.EXPECT().Compare(gomock.Eq([]byte("foo")), gomock.Eq([]byte("bar"))).Return(nil)
-				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				handler.EXPECT().RevokeToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			handlers: RevocationHandlers{handler},
+		},
+		{
+			header: http.Header{
+				"Authorization": {basicAuth("foo", "bar")},
+			},
+			method: "POST",
+			form: url.Values{
+				"token":           {"foo"},
+				"token_type_hint": {"id_token"},
+			},
+			expectErr: ErrUnsupportedTokenType,
+			mock: func() {
+				store.EXPECT().GetClient(gomock.Any(), gomock.Eq("foo")).Return(client, nil)
+				client.EXPECT().GetHashedSecret().Return([]byte("foo"))
+				client.EXPECT().IsPublic().Return(false)
+				hasher.EXPECT().Compare(gomock.Eq([]byte("foo")), gomock.Eq([]byte("bar"))).Return(nil)
+				handler.EXPECT().RevokeToken(gomock.Any(), "foo", TokenType("id_token"), client).Return(errors.WithStack(ErrUnsupportedTokenType))
 			},
 			handlers: RevocationHandlers{handler},
 		},
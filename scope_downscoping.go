@@ -0,0 +1,24 @@
+package fosite
+
+// GrantScopesRequester is implemented by requests which remember the scopes of the grant they belong to. Tokens issued
+// at the token endpoint may carry a subset of the scopes the resource owner granted, see
+// https://tools.ietf.org/html/rfc6749#section-6, while later refreshes may request any scope of the original grant.
+type GrantScopesRequester interface {
+	// GetGrantScopes returns the scopes granted by the resource owner for the whole grant, or nil if they are the
+	// request's granted scopes.
+	GetGrantScopes() (scopes Arguments)
+
+	// SetGrantScopes sets the scopes granted by the resource owner for the whole grant.
+	SetGrantScopes(scopes Arguments)
+}
+
+// GetGrantScopes returns the scopes granted by the resource owner for the grant a request belongs to. These are the
+// request's granted scopes unless its tokens were issued for a subset of them.
+func GetGrantScopes(r Requester) Arguments {
+	if gr, ok := r.(GrantScopesRequester); ok {
+		if scopes := gr.GetGrantScopes(); len(scopes) > 0 {
+			return scopes
+		}
+	}
+	return r.GetGrantedScopes()
+}
@@ -0,0 +1,171 @@
+package fosite
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+)
+
+// ScopeParameters are the typed values of the parameters of a parameterized scope, see ScopeTemplate.
+// Parameters of type "int" are int64 values, all other parameters are strings.
+type ScopeParameters map[string]interface{}
+
+// GetString returns the value of a string or uuid parameter.
+func (p ScopeParameters) GetString(name string) (string, bool) {
+	v, ok := p[name].(string)
+	return v, ok
+}
+
+// GetInt returns the value of an int parameter.
+func (p ScopeParameters) GetInt(name string) (int64, bool) {
+	v, ok := p[name].(int64)
+	return v, ok
+}
+
+// ScopeTemplate is a parameterized scope such as "document:read:{id:int}". Its segments are separated by ":" and a
+// segment "{name}" or "{name:type}" is a parameter matching exactly one non-empty segment. The supported types are
+// "string" (the default), "int" and "uuid".
+type ScopeTemplate struct {
+	raw      string
+	segments []scopeTemplateSegment
+}
+
+type scopeTemplateSegment struct {
+	literal   string
+	parameter string
+	kind      string
+}
+
+// IsScopeTemplate returns true if the scope contains parameters.
+func IsScopeTemplate(scope string) bool {
+	return strings.Contains(scope, "{")
+}
+
+// ParseScopeTemplate parses a parameterized scope.
+func ParseScopeTemplate(template string) (*ScopeTemplate, error) {
+	t := &ScopeTemplate{raw: template}
+	names := map[string]bool{}
+	for _, segment := range splitScopeTemplate(template) {
+		if !strings.HasPrefix(segment, "{") {
+			if strings.ContainsAny(segment, "{}") {
+				return nil, errors.Errorf("Scope template %s contains a malformed parameter", template)
+			}
+			t.segments = append(t.segments, scopeTemplateSegment{literal: segment})
+			continue
+		} else if !strings.HasSuffix(segment, "}") {
+			return nil, errors.Errorf("Scope template %s contains a malformed parameter", template)
+		}
+
+		parts := strings.SplitN(segment[1:len(segment)-1], ":", 2)
+		name, kind := parts[0], "string"
+		if len(parts) == 2 {
+			kind = parts[1]
+		}
+
+		switch {
+		case name == "" || strings.ContainsAny(name, "{}"):
+			return nil, errors.Errorf("Scope template %s contains a parameter without a name", template)
+		case names[name]:
+			return nil, errors.Errorf("Scope template %s contains parameter %s twice", template, name)
+		case kind != "string" && kind != "int" && kind != "uuid":
+			return nil, errors.Errorf("Scope template %s contains parameter %s of unknown type %s", template, name, kind)
+		}
+		names[name] = true
+		t.segments = append(t.segments, scopeTemplateSegment{parameter: name, kind: kind})
+	}
+	return t, nil
+}
+
+// String returns the template as it was parsed.
+func (t *ScopeTemplate) String() string {
+	return t.raw
+}
+
+// Match returns the parameters of the scope if it matches the template.
+func (t *ScopeTemplate) Match(scope string) (ScopeParameters, bool) {
+	segments := strings.Split(scope, ":")
+	if len(segments) != len(t.segments) {
+		return nil, false
+	}
+
+	parameters := ScopeParameters{}
+	for k, segment := range t.segments {
+		value := segments[k]
+		if segment.parameter == "" {
+			if value != segment.literal {
+				return nil, false
+			}
+			continue
+		} else if value == "" || strings.ContainsAny(value, "{}*") {
+			return nil, false
+		}
+
+		switch segment.kind {
+		case "int":
+			i, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, false
+			}
+			parameters[segment.parameter] = i
+		case "uuid":
+			if uuid.Parse(value) == nil {
+				return nil, false
+			}
+			parameters[segment.parameter] = value
+		default:
+			parameters[segment.parameter] = value
+		}
+	}
+	return parameters, true
+}
+
+// ParameterizedScopeStrategy matches scopes that are registered verbatim and scopes matching a registered
+// ScopeTemplate, e.g. "document:read:123" matches "document:read:{id:int}". Malformed templates never match.
+func ParameterizedScopeStrategy(haystack []string, needle string) bool {
+	_, ok := GetScopeParameters(haystack, needle)
+	return ok
+}
+
+// GetScopeParameters returns the parameters of the scope extracted using the first template of the haystack it
+// matches. A scope registered verbatim has no parameters.
+func GetScopeParameters(haystack []string, scope string) (ScopeParameters, bool) {
+	if ExactScopeStrategy(haystack, scope) {
+		return ScopeParameters{}, true
+	}
+
+	for _, this := range haystack {
+		if !IsScopeTemplate(this) {
+			continue
+		}
+
+		template, err := ParseScopeTemplate(this)
+		if err != nil {
+			continue
+		} else if parameters, ok := template.Match(scope); ok {
+			return parameters, true
+		}
+	}
+	return nil, false
+}
+
+// splitScopeTemplate splits a template at the ":" separators outside of parameters.
+func splitScopeTemplate(template string) []string {
+	var segments []string
+	var depth, start int
+	for i := 0; i < len(template); i++ {
+		switch template[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ':':
+			if depth == 0 {
+				segments = append(segments, template[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, template[start:])
+}
@@ -0,0 +1,89 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopeTemplate(t *testing.T) {
+	for k, c := range []struct {
+		template  string
+		expectErr bool
+	}{
+		{template: "document:read:{id:int}"},
+		{template: "document:{action}:{id:uuid}"},
+		{template: "user:{name:string}"},
+		{template: "document:read:{id:float}", expectErr: true},
+		{template: "document:read:{:int}", expectErr: true},
+		{template: "document:{id}:{id}", expectErr: true},
+		{template: "document:read:{id", expectErr: true},
+		{template: "document:re{ad}", expectErr: true},
+	} {
+		_, err := ParseScopeTemplate(c.template)
+		assert.Equal(t, c.expectErr, err != nil, "case %d: %s", k, err)
+	}
+}
+
+func TestScopeTemplateMatch(t *testing.T) {
+	template, err := ParseScopeTemplate("document:{action}:{id:int}")
+	require.Nil(t, err)
+	assert.Equal(t, "document:{action}:{id:int}", template.String())
+
+	parameters, ok := template.Match("document:read:123")
+	require.True(t, ok)
+	action, ok := parameters.GetString("action")
+	assert.True(t, ok)
+	assert.Equal(t, "read", action)
+	id, ok := parameters.GetInt("id")
+	assert.True(t, ok)
+	assert.Equal(t, int64(123), id)
+	_, ok = parameters.GetInt("action")
+	assert.False(t, ok)
+
+	for _, scope := range []string{"document:read:abc", "document:read", "document:read:123:456", "document::123", "folder:read:123", "document:*:123"} {
+		_, ok := template.Match(scope)
+		assert.False(t, ok, "%s", scope)
+	}
+
+	template, err = ParseScopeTemplate("tenant:{id:uuid}")
+	require.Nil(t, err)
+	_, ok = template.Match("tenant:9b2b3c3e-5b7a-4d55-9c33-7c8a3d7b7f2e")
+	assert.True(t, ok)
+	_, ok = template.Match("tenant:foo")
+	assert.False(t, ok)
+}
+
+func TestParameterizedScopeStrategy(t *testing.T) {
+	var strategy ScopeStrategy = ParameterizedScopeStrategy
+	scopes := []string{"openid", "document:read:{id:int}", "document:{invalid"}
+
+	assert.True(t, strategy(scopes, "openid"))
+	assert.True(t, strategy(scopes, "document:read:123"))
+	assert.False(t, strategy(scopes, "document:write:123"))
+	assert.False(t, strategy(scopes, "document:read:abc"))
+	assert.False(t, strategy(scopes, "document:{invalid:1"))
+
+	parameters, ok := GetScopeParameters(scopes, "document:read:42")
+	require.True(t, ok)
+	id, _ := parameters.GetInt("id")
+	assert.Equal(t, int64(42), id)
+
+	parameters, ok = GetScopeParameters(scopes, "openid")
+	require.True(t, ok)
+	assert.Empty(t, parameters)
+}
+
+func TestScopeRegistryWithTemplates(t *testing.T) {
+	registry := ScopeRegistry{
+		"document:read:{id:int}": {Description: "Read a document"},
+	}
+
+	metadata, ok := registry.Get("document:read:123")
+	require.True(t, ok)
+	assert.Equal(t, "Read a document", metadata.Description)
+
+	_, ok = registry.Get("document:read:abc")
+	assert.False(t, ok)
+}
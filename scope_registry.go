@@ -0,0 +1,129 @@
+package fosite
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ScopeMetadata describes a scope registered in a ScopeRegistry.
+type ScopeMetadata struct {
+	// Description is shown to the subject, e.g. on consent screens.
+	Description string `json:"description,omitempty"`
+
+	// SkipConsent is true if the subject does not need to approve the scope. ConsentStrategy grants such scopes
+	// automatically unless they are sensitive. All other scopes require consent.
+	SkipConsent bool `json:"skip_consent,omitempty"`
+
+	// RefreshIneligible is true if access tokens issued by the refresh token grant must not carry the scope. Such scopes
+	// are dropped when the grant is refreshed.
+	RefreshIneligible bool `json:"refresh_ineligible,omitempty"`
+
+	// Sensitive is true if the subject must approve the scope every time. ConsentStrategy neither grants sensitive
+	// scopes automatically nor from remembered consent.
+	Sensitive bool `json:"sensitive,omitempty"`
+
+	// Default is true if the scope is requested when the scope parameter is omitted.
+	Default bool `json:"default,omitempty"`
+}
+
+// ScopeRegistry is a registry of the scopes an authorization server supports, mapping each scope to its metadata.
+// Scopes are looked up hierarchically like HierarchicScopeStrategy matches them, so registering "photos" also
+// supports "photos.read", and parameterized scopes are registered as ScopeTemplate, e.g. "document:read:{id:int}".
+type ScopeRegistry map[string]ScopeMetadata
+
+// Get returns the metadata of the scope, of the first registered ScopeTemplate matching the scope, e.g. of
+// "document:read:{id:int}" for "document:read:123", or of the closest registered parent of the scope, e.g. of
+// "photos" for "photos.read".
+func (r ScopeRegistry) Get(scope string) (ScopeMetadata, bool) {
+	if metadata, ok := r[scope]; ok {
+		return metadata, true
+	}
+
+	for _, name := range r.GetScopesSupported() {
+		if !IsScopeTemplate(name) {
+			continue
+		} else if template, err := ParseScopeTemplate(name); err != nil {
+			continue
+		} else if _, ok := template.Match(scope); ok {
+			return r[name], true
+		}
+	}
+
+	for scope != "" {
+		if metadata, ok := r[scope]; ok {
+			return metadata, true
+		}
+
+		i := strings.LastIndex(scope, ".")
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
+	}
+	return ScopeMetadata{}, false
+}
+
+// IsRefreshEligible returns true if the scope may be carried by refreshed access tokens, which is the case unless it is
+// registered as RefreshIneligible. "offline" is always refresh eligible.
+func (r ScopeRegistry) IsRefreshEligible(scope string) bool {
+	if r == nil || scope == "offline" {
+		return true
+	}
+	metadata, ok := r.Get(scope)
+	return !ok || !metadata.RefreshIneligible
+}
+
+// GetDefaultScopes returns the sorted scopes requested when the scope parameter is omitted.
+func (r ScopeRegistry) GetDefaultScopes() Arguments {
+	scopes := Arguments{}
+	for scope, metadata := range r {
+		if metadata.Default {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// GetScopesSupported returns the sorted registered scopes, e.g. for the scopes_supported member of the
+// authorization server's discovery metadata (RFC 8414 and OpenID Connect Discovery).
+func (r ScopeRegistry) GetScopesSupported() []string {
+	scopes := make([]string, 0, len(r))
+	for scope := range r {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// validateScopes rejects requested scopes which are not registered in the ScopeRegistry. If no scope was requested
+// and applyDefaults is true, the registry's default scopes the client may request are requested instead.
+func (f *Fosite) validateScopes(request Requester, applyDefaults bool) error {
+	if f.ScopeRegistry == nil {
+		return nil
+	}
+
+	if len(request.GetRequestedScopes()) == 0 && applyDefaults {
+		strategy := f.ScopeStrategy
+		if strategy == nil {
+			strategy = HierarchicScopeStrategy
+		}
+
+		scopes := Arguments{}
+		for _, scope := range f.ScopeRegistry.GetDefaultScopes() {
+			if strategy(request.GetClient().GetScopes(), scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+		request.SetRequestedScopes(scopes)
+	}
+
+	for _, scope := range request.GetRequestedScopes() {
+		if _, ok := f.ScopeRegistry.Get(scope); !ok {
+			return errors.Wrapf(ErrInvalidScope, "The scope %s is not supported", scope)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package fosite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testScopeRegistry = ScopeRegistry{
+	"openid":  {Description: "Sign you in", Default: true, SkipConsent: true},
+	"offline": {Description: "Keep you signed in"},
+	"photos":  {Description: "Access your photos", Default: true, RefreshIneligible: true},
+	"admin":   {Description: "Manage your account", Sensitive: true},
+}
+
+func TestScopeRegistry(t *testing.T) {
+	metadata, ok := testScopeRegistry.Get("photos.read")
+	require.True(t, ok)
+	assert.Equal(t, "Access your photos", metadata.Description)
+
+	_, ok = testScopeRegistry.Get("photosfoo")
+	assert.False(t, ok)
+	_, ok = testScopeRegistry.Get("contacts.read")
+	assert.False(t, ok)
+
+	assert.Equal(t, Arguments{"openid", "photos"}, testScopeRegistry.GetDefaultScopes())
+	assert.Equal(t, []string{"admin", "offline", "openid", "photos"}, testScopeRegistry.GetScopesSupported())
+
+	assert.True(t, testScopeRegistry.IsRefreshEligible("openid"))
+	assert.True(t, testScopeRegistry.IsRefreshEligible("offline"))
+	assert.True(t, testScopeRegistry.IsRefreshEligible("unknown"))
+	assert.False(t, testScopeRegistry.IsRefreshEligible("photos.read"))
+	assert.True(t, ScopeRegistry(nil).IsRefreshEligible("photos"))
+}
+
+func TestValidateScopes(t *testing.T) {
+	f := &Fosite{ScopeRegistry: testScopeRegistry, ScopeStrategy: HierarchicScopeStrategy}
+	client := &DefaultClient{Scopes: []string{"openid", "photos", "offline"}}
+
+	for k, c := range []struct {
+		requested     Arguments
+		applyDefaults bool
+		expect        Arguments
+		expectErr     error
+	}{
+		{requested: Arguments{"openid", "photos.read"}, expect: Arguments{"openid", "photos.read"}},
+		{requested: Arguments{"openid", "contacts"}, expectErr: ErrInvalidScope},
+		{requested: Arguments{}, applyDefaults: true, expect: Arguments{"openid", "photos"}},
+		{requested: Arguments{}},
+	} {
+		request := NewRequest()
+		request.Client = client
+		request.SetRequestedScopes(c.requested)
+
+		err := f.validateScopes(request, c.applyDefaults)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s", k, err)
+		if err == nil {
+			assert.Equal(t, c.expect, request.GetRequestedScopes(), "%d", k)
+		}
+	}
+
+	// Default scopes the client may not request are skipped.
+	request := NewRequest()
+	request.Client = &DefaultClient{Scopes: []string{"openid"}}
+	require.Nil(t, f.validateScopes(request, true))
+	assert.Equal(t, Arguments{"openid"}, request.GetRequestedScopes())
+
+	// Without a registry every scope is accepted.
+	request.SetRequestedScopes(Arguments{"contacts"})
+	assert.Nil(t, (&Fosite{}).validateScopes(request, true))
+}
+
+func TestConsentStrategyWithScopeRegistry(t *testing.T) {
+	record := &ConsentRecord{Subject: "peter", ClientID: "foo", GrantedScopes: Arguments{"photos", "admin"}}
+	s := &ConsentStrategy{Storage: &staticConsentStorage{record: record}, ScopeRegistry: testScopeRegistry}
+
+	ar := NewAuthorizeRequest()
+	ar.Client = &DefaultClient{ID: "foo"}
+	ar.SetRequestedScopes(Arguments{"openid", "photos", "admin", "offline"})
+
+	decision, err := s.HandleConsent(nil, "peter", ar)
+	require.Nil(t, err)
+	assert.Equal(t, Arguments{"openid", "photos"}, decision.GrantedScopes)
+	assert.Equal(t, Arguments{"admin", "offline"}, decision.PendingScopes)
+
+	// Scopes require consent unless registered with SkipConsent, and sensitive scopes always require consent.
+	s.ScopeRegistry = ScopeRegistry{"profile": {}, "audit": {SkipConsent: true, Sensitive: true}, "email": {SkipConsent: true}}
+	s.Storage = &staticConsentStorage{}
+	ar = NewAuthorizeRequest()
+	ar.Client = &DefaultClient{ID: "foo"}
+	ar.SetRequestedScopes(Arguments{"profile", "audit", "email"})
+
+	decision, err = s.HandleConsent(nil, "peter", ar)
+	require.Nil(t, err)
+	assert.Equal(t, Arguments{"email"}, decision.GrantedScopes)
+	assert.Equal(t, Arguments{"profile", "audit"}, decision.PendingScopes)
+}
+
+type staticConsentStorage struct {
+	ConsentStorage
+	record *ConsentRecord
+}
+
+func (s *staticConsentStorage) GetConsent(_ context.Context, subject, clientID string) (*ConsentRecord, error) {
+	if s.record == nil || s.record.Subject != subject || s.record.ClientID != clientID {
+		return nil, ErrNotFound
+	}
+	return s.record, nil
+}
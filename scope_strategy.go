@@ -34,3 +34,72 @@ func HierarchicScopeStrategy(haystack []string, needle string) bool {
 
 	return false
 }
+
+// ExactScopeStrategy only matches scopes that are registered verbatim.
+func ExactScopeStrategy(haystack []string, needle string) bool {
+	for _, this := range haystack {
+		if this == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// WildcardScopeStrategy matches scopes that are registered verbatim and scopes matching a registered pattern. Scopes
+// consist of segments separated by "." or ":" and a "*" segment in a pattern matches exactly one segment, for
+// example:
+//
+// * "photos.*" matches "photos.read" but neither "photos" nor "photos.read.all",
+// * "read:*" matches "read:photos",
+// * "*.read" matches "photos.read".
+//
+// A requested scope containing "*" only matches the same pattern.
+func WildcardScopeStrategy(haystack []string, needle string) bool {
+	if ExactScopeStrategy(haystack, needle) {
+		return true
+	} else if strings.Contains(needle, "*") {
+		return false
+	}
+
+	needleSegments, needleSeparators := splitScope(needle)
+	for _, this := range haystack {
+		if !strings.Contains(this, "*") {
+			continue
+		}
+
+		segments, separators := splitScope(this)
+		if len(segments) != len(needleSegments) || separators != needleSeparators {
+			continue
+		}
+
+		matches := true
+		for k, segment := range segments {
+			if segment == "*" && needleSegments[k] != "" {
+				continue
+			} else if segment != needleSegments[k] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// splitScope splits a scope into its segments and returns them together with the separators in order. Empty
+// segments, e.g. in "photos..read", are kept so that they never match a wildcard.
+func splitScope(scope string) ([]string, string) {
+	var segments []string
+	var separators []byte
+	start := 0
+	for i := 0; i < len(scope); i++ {
+		if scope[i] == '.' || scope[i] == ':' {
+			segments = append(segments, scope[start:i])
+			separators = append(separators, scope[i])
+			start = i + 1
+		}
+	}
+	return append(segments, scope[start:]), string(separators)
+}
@@ -41,3 +41,41 @@ func TestHierarchicScopeStrategy(t *testing.T) {
 	assert.True(t, strategy(scopes, "offline"))
 	assert.True(t, strategy(scopes, "offline.baz.bar.baz"))
 }
+
+func TestExactScopeStrategy(t *testing.T) {
+	var strategy ScopeStrategy = ExactScopeStrategy
+	scopes := []string{"foo.bar", "baz"}
+
+	assert.True(t, strategy(scopes, "foo.bar"))
+	assert.True(t, strategy(scopes, "baz"))
+	assert.False(t, strategy(scopes, "foo.bar.baz"))
+	assert.False(t, strategy(scopes, "foo"))
+	assert.False(t, strategy(scopes, "Baz"))
+}
+
+func TestWildcardScopeStrategy(t *testing.T) {
+	var strategy ScopeStrategy = WildcardScopeStrategy
+	scopes := []string{"photos.*", "read:*", "*.write", "openid"}
+
+	for k, c := range []struct {
+		scope  string
+		expect bool
+	}{
+		{scope: "openid", expect: true},
+		{scope: "photos.*", expect: true},
+		{scope: "photos.read", expect: true},
+		{scope: "photos", expect: false},
+		{scope: "photos.", expect: false},
+		{scope: "photos.read.all", expect: false},
+		{scope: "photos:read", expect: false},
+		{scope: "read:photos", expect: true},
+		{scope: "read:*", expect: true},
+		{scope: "read:photos:all", expect: false},
+		{scope: "contacts.write", expect: true},
+		{scope: "contacts.*", expect: false},
+		{scope: "contacts.read", expect: false},
+		{scope: "write", expect: false},
+	} {
+		assert.Equal(t, c.expect, strategy(scopes, c.scope), "case %d: %s", k, c.scope)
+	}
+}
@@ -1,8 +1,6 @@
 package fosite
 
 import (
-	"bytes"
-	"encoding/gob"
 	"time"
 )
 
@@ -29,11 +27,32 @@ type Session interface {
 	Clone() Session
 }
 
+// IntrospectionClaimsSession is an optional extension of Session for sessions that add custom top-level members, e.g.
+// a tenant or roles, to introspection responses. Custom members never override the standard members.
+type IntrospectionClaimsSession interface {
+	// GetIntrospectionClaims returns the custom members of introspection responses.
+	GetIntrospectionClaims() map[string]interface{}
+
+	Session
+}
+
+// TokenIDSession is an optional extension of Session for sessions of self-contained tokens which carry a unique
+// identifier, e.g. the "jti" claim of JWT access tokens. It is returned as the "jti" member of introspection responses.
+type TokenIDSession interface {
+	// GetTokenID returns the identifier of the token, or an empty string if the token has none.
+	GetTokenID() string
+
+	Session
+}
+
 // DefaultSession is a default implementation of the session interface.
 type DefaultSession struct {
 	ExpiresAt map[TokenType]time.Time
 	Username  string
 	Subject   string
+
+	// Extra contains custom members of introspection responses.
+	Extra map[string]interface{}
 }
 
 func (s *DefaultSession) SetExpiresAt(key TokenType, exp time.Time) {
@@ -69,16 +88,56 @@ func (s *DefaultSession) GetSubject() string {
 	return s.Subject
 }
 
+func (s *DefaultSession) GetIntrospectionClaims() map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+
+	return s.Extra
+}
+
 func (s *DefaultSession) Clone() Session {
 	if s == nil {
 		return nil
 	}
 
-	var clone DefaultSession
-	var mod bytes.Buffer
-	enc := gob.NewEncoder(&mod)
-	dec := gob.NewDecoder(&mod)
-	_ = enc.Encode(s)
-	_ = dec.Decode(&clone)
-	return &clone
+	clone := &DefaultSession{
+		Username: s.Username,
+		Subject:  s.Subject,
+	}
+	if s.ExpiresAt != nil {
+		clone.ExpiresAt = make(map[TokenType]time.Time, len(s.ExpiresAt))
+		for k, v := range s.ExpiresAt {
+			clone.ExpiresAt[k] = v
+		}
+	}
+	if s.Extra != nil {
+		clone.Extra = copyClaims(s.Extra)
+	}
+	return clone
+}
+
+// copyClaims deep copies JSON-shaped claims, i.e. nested maps and slices. Other values are copied as they are.
+func copyClaims(claims map[string]interface{}) map[string]interface{} {
+	clone := make(map[string]interface{}, len(claims))
+	for k, v := range claims {
+		clone[k] = copyClaim(v)
+	}
+	return clone
+}
+
+func copyClaim(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return copyClaims(t)
+	case []interface{}:
+		clone := make([]interface{}, len(t))
+		for i, e := range t {
+			clone[i] = copyClaim(e)
+		}
+		return clone
+	case []string:
+		return append([]string(nil), t...)
+	}
+	return v
 }
@@ -0,0 +1,160 @@
+package fosite
+
+import (
+	"encoding/json"
+	"strings"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+// softwareStatementSigningMethods are the asymmetric algorithms software statements may be signed with.
+var softwareStatementSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// softwareStatementRegisteredClaims are the JWT claims of a software statement that are not client metadata.
+var softwareStatementRegisteredClaims = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+// SoftwareStatementStrategy verifies software statements, signed JWTs asserting client metadata as defined in
+// https://tools.ietf.org/html/rfc7591#section-2.3
+//
+//   The software statement is a digitally signed or MACed JWT [RFC7519]
+//   [...] The JWT MUST contain an "iss" (issuer) claim denoting the party
+//   attesting to the claims in the software statement.
+//
+// Only statements of trusted issuers signed with an asymmetric algorithm are accepted.
+type SoftwareStatementStrategy struct {
+	// TrustedIssuers maps the issuers whose statements are accepted to their public keys (*rsa.PublicKey or
+	// *ecdsa.PublicKey). ParseJSONWebKeySet can be used to load an issuer's JWK set.
+	TrustedIssuers map[string][]interface{}
+}
+
+// Verify verifies the signature, issuer and expiry of a software statement and returns its claims.
+// Statements of unknown issuers are rejected with ErrUnapprovedSoftwareStatement, all other problems are reported as
+// ErrInvalidSoftwareStatement.
+func (s *SoftwareStatementStrategy) Verify(statement string) (map[string]interface{}, error) {
+	parts := strings.Split(statement, ".")
+	if len(parts) != 3 {
+		return nil, errors.Wrap(ErrInvalidSoftwareStatement, "Software statement is not a signed JWT")
+	}
+
+	payload, err := jwtx.DecodeSegment(parts[1])
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSoftwareStatement, err.Error())
+	}
+
+	var unverified struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &unverified); err != nil {
+		return nil, errors.Wrap(ErrInvalidSoftwareStatement, err.Error())
+	} else if unverified.Issuer == "" {
+		return nil, errors.Wrap(ErrInvalidSoftwareStatement, "Software statement has no iss claim")
+	}
+
+	keys, ok := s.TrustedIssuers[unverified.Issuer]
+	if !ok || len(keys) == 0 {
+		return nil, errors.Wrapf(ErrUnapprovedSoftwareStatement, "Software statement issuer %s is not trusted", unverified.Issuer)
+	}
+
+	parser := &jwtx.Parser{ValidMethods: softwareStatementSigningMethods}
+	err = errors.New("Software statement signature is invalid")
+	for _, key := range keys {
+		claims := jwtx.MapClaims{}
+		if _, err = parser.ParseWithClaims(statement, claims, func(*jwtx.Token) (interface{}, error) {
+			return key, nil
+		}); err == nil {
+			return claims, nil
+		}
+	}
+
+	return nil, errors.Wrap(ErrInvalidSoftwareStatement, err.Error())
+}
+
+// ParseJSONWebKeySet returns the RSA and EC public keys of a JWK set.
+func ParseJSONWebKeySet(raw []byte) ([]interface{}, error) {
+	var set struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, errors.WithStack(err)
+	} else if len(set.Keys) == 0 {
+		return nil, errors.New("The key set contains no keys")
+	}
+
+	keys := make([]interface{}, len(set.Keys))
+	for i, jwk := range set.Keys {
+		key, _, err := parseDPoPJWK(jwk)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// SoftwareStatementPolicy decides whether a client must be registered with a verified software statement. It is
+// called with the metadata the request's software statement does not assert, so values requiring a statement must be
+// asserted by one.
+type SoftwareStatementPolicy interface {
+	RequiresSoftwareStatement(metadata *ClientMetadata) bool
+}
+
+// RequireSoftwareStatementFor requires a software statement from clients registering any of the scopes or grant
+// types.
+type RequireSoftwareStatementFor struct {
+	Scopes     []string
+	GrantTypes []string
+}
+
+func (p *RequireSoftwareStatementFor) RequiresSoftwareStatement(metadata *ClientMetadata) bool {
+	for _, scope := range strings.Split(metadata.Scope, " ") {
+		if scope != "" && StringInSlice(scope, p.Scopes) {
+			return true
+		}
+	}
+	for _, grantType := range metadata.GrantTypes {
+		if StringInSlice(grantType, p.GrantTypes) {
+			return true
+		}
+	}
+	return false
+}
+
+// applySoftwareStatement verifies the software statement of the submitted metadata and overwrites the submitted
+// values with the statement's claims, see https://tools.ietf.org/html/rfc7591#section-2.3. The submitted values the
+// statement does not assert are returned as unsigned.
+//
+//   If the authorization server determines that the claims in a software
+//   statement uniquely identify a piece of software, the same software
+//   statement MAY be presented by multiple instances of that software.
+//   [...] If a software statement is used and a value is found both in the
+//   software statement and in the registration request, the value from
+//   the software statement SHALL take precedence.
+func applySoftwareStatement(strategy *SoftwareStatementStrategy, submitted map[string]interface{}) (merged, unsigned map[string]interface{}, err error) {
+	statement, ok := submitted["software_statement"].(string)
+	if !ok {
+		return submitted, submitted, nil
+	} else if strategy == nil {
+		return nil, nil, errors.Wrap(ErrUnapprovedSoftwareStatement, "Software statements are not accepted")
+	}
+
+	claims, err := strategy.Verify(statement)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged = map[string]interface{}{}
+	unsigned = map[string]interface{}{}
+	for k, v := range submitted {
+		merged[k] = v
+		if _, ok := claims[k]; !ok && k != "software_statement" {
+			unsigned[k] = v
+		}
+	}
+	for k, v := range claims {
+		if !StringInSlice(k, softwareStatementRegisteredClaims) {
+			merged[k] = v
+		}
+	}
+	return merged, unsigned, nil
+}
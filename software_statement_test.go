@@ -0,0 +1,160 @@
+package fosite_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	jwtx "github.com/dgrijalva/jwt-go"
+	. "github.com/ory/fosite"
+	"github.com/ory/fosite/internal"
+	"github.com/ory/fosite/storage"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftwareStatementStrategy(t *testing.T) {
+	key := internal.MustRSAKey()
+	otherKey := internal.MustRSAKey()
+	s := &SoftwareStatementStrategy{
+		TrustedIssuers: map[string][]interface{}{
+			"https://directory.example.com": {&otherKey.PublicKey, &key.PublicKey},
+		},
+	}
+
+	sign := func(method jwtx.SigningMethod, k interface{}, claims jwtx.MapClaims) string {
+		token, err := jwtx.NewWithClaims(method, claims).SignedString(k)
+		require.Nil(t, err)
+		return token
+	}
+
+	claims, err := s.Verify(sign(jwtx.SigningMethodRS256, key, jwtx.MapClaims{"iss": "https://directory.example.com", "software_id": "foo"}))
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "foo", claims["software_id"])
+
+	for k, c := range []struct {
+		description string
+		statement   string
+		expectErr   error
+	}{
+		{
+			description: "should fail because the issuer is not trusted",
+			statement:   sign(jwtx.SigningMethodRS256, key, jwtx.MapClaims{"iss": "https://other.example.com"}),
+			expectErr:   ErrUnapprovedSoftwareStatement,
+		},
+		{
+			description: "should fail because the issuer is missing",
+			statement:   sign(jwtx.SigningMethodRS256, key, jwtx.MapClaims{"software_id": "foo"}),
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+		{
+			description: "should fail because the signing key is not trusted",
+			statement:   sign(jwtx.SigningMethodRS256, internal.MustRSAKey(), jwtx.MapClaims{"iss": "https://directory.example.com"}),
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+		{
+			description: "should fail because symmetric signatures are not accepted",
+			statement:   sign(jwtx.SigningMethodHS256, []byte("secret"), jwtx.MapClaims{"iss": "https://directory.example.com"}),
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+		{
+			description: "should fail because the statement expired",
+			statement:   sign(jwtx.SigningMethodRS256, key, jwtx.MapClaims{"iss": "https://directory.example.com", "exp": float64(time.Now().Add(-time.Hour).Unix())}),
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+		{
+			description: "should fail because the statement is not a JWT",
+			statement:   "foo",
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+	} {
+		_, err := s.Verify(c.statement)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+}
+
+func TestClientRegistrationWithSoftwareStatement(t *testing.T) {
+	key := internal.MustRSAKey()
+	store := storage.NewMemoryStore()
+	h := &ClientRegistrationHandler{
+		Store:                store,
+		Hasher:               &BCrypt{WorkFactor: 4},
+		RegistrationEndpoint: "https://server.example.com/register",
+		SoftwareStatementStrategy: &SoftwareStatementStrategy{
+			TrustedIssuers: map[string][]interface{}{"https://directory.example.com": {&key.PublicKey}},
+		},
+		SoftwareStatementPolicy: &RequireSoftwareStatementFor{Scopes: []string{"payments"}, GrantTypes: []string{"client_credentials"}},
+	}
+
+	statement, err := jwtx.NewWithClaims(jwtx.SigningMethodRS256, jwtx.MapClaims{
+		"iss":           "https://directory.example.com",
+		"iat":           float64(time.Now().Unix()),
+		"software_id":   "partner-app",
+		"redirect_uris": []string{"https://partner.example.com/cb"},
+		"scope":         "openid payments",
+	}).SignedString(key)
+	require.Nil(t, err)
+
+	res, err := h.RegisterClient(nil, newRegistrationRequest("POST", `{
+		"software_statement": "`+statement+`",
+		"redirect_uris": ["https://evil.example.com/cb"],
+		"scope": "openid payments admin",
+		"software_version": "1.0"
+	}`, ""))
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, []string{"https://partner.example.com/cb"}, res.RedirectURIs)
+	assert.Equal(t, "openid payments", res.Scope)
+	assert.Equal(t, "partner-app", res.SoftwareID)
+	assert.Equal(t, "1.0", res.SoftwareVersion)
+	assert.Equal(t, statement, res.SoftwareStatement)
+	assert.Equal(t, "partner-app", store.OpenIDConnectClients[res.ClientID].SoftwareID)
+
+	unscoped, err := jwtx.NewWithClaims(jwtx.SigningMethodRS256, jwtx.MapClaims{
+		"iss":           "https://directory.example.com",
+		"software_id":   "partner-app",
+		"redirect_uris": []string{"https://partner.example.com/cb"},
+	}).SignedString(key)
+	require.Nil(t, err)
+
+	for k, c := range []struct {
+		description string
+		request     *http.Request
+		expectErr   error
+	}{
+		{
+			description: "should fail because the scope is not asserted by the software statement",
+			request:     newRegistrationRequest("POST", `{"software_statement":"`+unscoped+`","scope":"openid payments"}`, ""),
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+		{
+			description: "should pass because the unsigned scope does not require a software statement",
+			request:     newRegistrationRequest("POST", `{"software_statement":"`+unscoped+`","scope":"openid"}`, ""),
+		},
+		{
+			description: "should fail because the scope requires a software statement",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"scope":"payments"}`, ""),
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+		{
+			description: "should fail because the grant type requires a software statement",
+			request:     newRegistrationRequest("POST", `{"grant_types":["client_credentials"],"response_types":["none"]}`, ""),
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+		{
+			description: "should fail because the software statement is invalid",
+			request:     newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"software_statement":"foo.bar.baz"}`, ""),
+			expectErr:   ErrInvalidSoftwareStatement,
+		},
+	} {
+		_, err := h.RegisterClient(nil, c.request)
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+
+	res, err = h.RegisterClient(nil, newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"scope":"openid"}`, ""))
+	require.Nil(t, err, "%s", err)
+
+	h.SoftwareStatementStrategy = nil
+	_, err = h.RegisterClient(nil, newRegistrationRequest("POST", `{"redirect_uris":["https://foo.example.com/cb"],"software_statement":"`+statement+`"}`, ""))
+	assert.True(t, errors.Cause(err) == ErrUnapprovedSoftwareStatement, "%s", err)
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/ory/fosite"
 	"github.com/pkg/errors"
@@ -13,35 +14,58 @@ type MemoryUserRelation struct {
 }
 
 type MemoryStore struct {
-	Clients        map[string]*fosite.DefaultClient
-	AuthorizeCodes map[string]fosite.Requester
-	IDSessions     map[string]fosite.Requester
-	AccessTokens   map[string]fosite.Requester
-	Implicit       map[string]fosite.Requester
-	RefreshTokens  map[string]fosite.Requester
-	Users          map[string]MemoryUserRelation
+	Clients map[string]*fosite.DefaultClient
+	// Clients with OpenID Connect client metadata, e.g. created by dynamic client registration
+	OpenIDConnectClients map[string]*fosite.DefaultOpenIDConnectClient
+	AuthorizeCodes       map[string]fosite.Requester
+	IDSessions           map[string]fosite.Requester
+	AccessTokens         map[string]fosite.Requester
+	Implicit             map[string]fosite.Requester
+	RefreshTokens        map[string]fosite.Requester
+	Users                map[string]MemoryUserRelation
 	// In-memory request ID to token signatures
 	AccessTokenRequestIDs  map[string]string
 	RefreshTokenRequestIDs map[string]string
+	// Used DPoP proof identifiers and when they expire
+	DPoPProofs map[string]time.Time
+	// In-memory subject and client ID to the tokens issued for them, used for bulk revocation
+	SubjectTokens map[string]map[MemoryTokenRelation]bool
+	ClientTokens  map[string]map[MemoryTokenRelation]bool
+	// In-memory login session ID to the IDs of the clients that took part in it
+	LoginSessionClients map[string][]string
+	// In-memory subject to client ID to the consent the subject granted to the client
+	Consents map[string]map[string]fosite.ConsentRecord
+}
+
+// MemoryTokenRelation identifies a stored token by its type and the key it is stored under.
+type MemoryTokenRelation struct {
+	TokenType fosite.TokenType
+	Key       string
 }
 
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		Clients:        make(map[string]*fosite.DefaultClient),
-		AuthorizeCodes: make(map[string]fosite.Requester),
-		IDSessions:     make(map[string]fosite.Requester),
-		AccessTokens:   make(map[string]fosite.Requester),
-		Implicit:       make(map[string]fosite.Requester),
-		RefreshTokens:  make(map[string]fosite.Requester),
-		Users:          make(map[string]MemoryUserRelation),
+		Clients:                make(map[string]*fosite.DefaultClient),
+		OpenIDConnectClients:   make(map[string]*fosite.DefaultOpenIDConnectClient),
+		AuthorizeCodes:         make(map[string]fosite.Requester),
+		IDSessions:             make(map[string]fosite.Requester),
+		AccessTokens:           make(map[string]fosite.Requester),
+		Implicit:               make(map[string]fosite.Requester),
+		RefreshTokens:          make(map[string]fosite.Requester),
+		Users:                  make(map[string]MemoryUserRelation),
 		AccessTokenRequestIDs:  make(map[string]string),
 		RefreshTokenRequestIDs: make(map[string]string),
+		DPoPProofs:             make(map[string]time.Time),
+		SubjectTokens:          make(map[string]map[MemoryTokenRelation]bool),
+		ClientTokens:           make(map[string]map[MemoryTokenRelation]bool),
+		Consents:               make(map[string]map[string]fosite.ConsentRecord),
 	}
 }
 
 func NewExampleStore() *MemoryStore {
 	return &MemoryStore{
-		IDSessions: make(map[string]fosite.Requester),
+		IDSessions:           make(map[string]fosite.Requester),
+		OpenIDConnectClients: make(map[string]*fosite.DefaultOpenIDConnectClient),
 		Clients: map[string]*fosite.DefaultClient{
 			"my-client": {
 				ID:            "my-client",
@@ -66,11 +90,16 @@ func NewExampleStore() *MemoryStore {
 		RefreshTokens:          map[string]fosite.Requester{},
 		AccessTokenRequestIDs:  map[string]string{},
 		RefreshTokenRequestIDs: map[string]string{},
+		DPoPProofs:             map[string]time.Time{},
+		SubjectTokens:          map[string]map[MemoryTokenRelation]bool{},
+		ClientTokens:           map[string]map[MemoryTokenRelation]bool{},
+		Consents:               map[string]map[string]fosite.ConsentRecord{},
 	}
 }
 
 func (s *MemoryStore) CreateOpenIDConnectSession(_ context.Context, authorizeCode string, requester fosite.Requester) error {
 	s.IDSessions[authorizeCode] = requester
+	s.indexToken(fosite.IDToken, authorizeCode, requester)
 	return nil
 }
 
@@ -82,21 +111,69 @@ func (s *MemoryStore) GetOpenIDConnectSession(_ context.Context, authorizeCode s
 	return cl, nil
 }
 
+func (s *MemoryStore) CreateClient(_ context.Context, client fosite.Client) error {
+	if s.hasClient(client.GetID()) {
+		return errors.Errorf("Client %s already exists", client.GetID())
+	}
+	return s.storeClient(client)
+}
+
+func (s *MemoryStore) UpdateClient(_ context.Context, client fosite.Client) error {
+	if !s.hasClient(client.GetID()) {
+		return fosite.ErrNotFound
+	}
+	return s.storeClient(client)
+}
+
+func (s *MemoryStore) DeleteClient(_ context.Context, id string) error {
+	if !s.hasClient(id) {
+		return fosite.ErrNotFound
+	}
+	delete(s.Clients, id)
+	delete(s.OpenIDConnectClients, id)
+	return nil
+}
+
+func (s *MemoryStore) hasClient(id string) bool {
+	_, ok := s.Clients[id]
+	_, oidc := s.OpenIDConnectClients[id]
+	return ok || oidc
+}
+
+func (s *MemoryStore) storeClient(client fosite.Client) error {
+	switch cl := client.(type) {
+	case *fosite.DefaultClient:
+		delete(s.OpenIDConnectClients, cl.ID)
+		s.Clients[cl.ID] = cl
+	case *fosite.DefaultOpenIDConnectClient:
+		delete(s.Clients, cl.ID)
+		s.OpenIDConnectClients[cl.ID] = cl
+	default:
+		return errors.New("MemoryStore can only store *fosite.DefaultClient and *fosite.DefaultOpenIDConnectClient")
+	}
+	return nil
+}
+
 func (s *MemoryStore) DeleteOpenIDConnectSession(_ context.Context, authorizeCode string) error {
+	if req, ok := s.IDSessions[authorizeCode]; ok {
+		s.unindexToken(fosite.IDToken, authorizeCode, req)
+	}
 	delete(s.IDSessions, authorizeCode)
 	return nil
 }
 
 func (s *MemoryStore) GetClient(_ context.Context, id string) (fosite.Client, error) {
-	cl, ok := s.Clients[id]
-	if !ok {
-		return nil, fosite.ErrNotFound
+	if cl, ok := s.Clients[id]; ok {
+		return cl, nil
+	} else if cl, ok := s.OpenIDConnectClients[id]; ok {
+		return cl, nil
 	}
-	return cl, nil
+	return nil, fosite.ErrNotFound
 }
 
 func (s *MemoryStore) CreateAuthorizeCodeSession(_ context.Context, code string, req fosite.Requester) error {
 	s.AuthorizeCodes[code] = req
+	s.indexToken(fosite.AuthorizeCode, code, req)
 	return nil
 }
 
@@ -109,6 +186,9 @@ func (s *MemoryStore) GetAuthorizeCodeSession(_ context.Context, code string, _
 }
 
 func (s *MemoryStore) DeleteAuthorizeCodeSession(_ context.Context, code string) error {
+	if req, ok := s.AuthorizeCodes[code]; ok {
+		s.unindexToken(fosite.AuthorizeCode, code, req)
+	}
 	delete(s.AuthorizeCodes, code)
 	return nil
 }
@@ -116,6 +196,7 @@ func (s *MemoryStore) DeleteAuthorizeCodeSession(_ context.Context, code string)
 func (s *MemoryStore) CreateAccessTokenSession(_ context.Context, signature string, req fosite.Requester) error {
 	s.AccessTokens[signature] = req
 	s.AccessTokenRequestIDs[req.GetID()] = signature
+	s.indexToken(fosite.AccessToken, signature, req)
 	return nil
 }
 
@@ -128,6 +209,9 @@ func (s *MemoryStore) GetAccessTokenSession(_ context.Context, signature string,
 }
 
 func (s *MemoryStore) DeleteAccessTokenSession(_ context.Context, signature string) error {
+	if req, ok := s.AccessTokens[signature]; ok {
+		s.unindexToken(fosite.AccessToken, signature, req)
+	}
 	delete(s.AccessTokens, signature)
 	return nil
 }
@@ -135,6 +219,7 @@ func (s *MemoryStore) DeleteAccessTokenSession(_ context.Context, signature stri
 func (s *MemoryStore) CreateRefreshTokenSession(_ context.Context, signature string, req fosite.Requester) error {
 	s.RefreshTokens[signature] = req
 	s.RefreshTokenRequestIDs[req.GetID()] = signature
+	s.indexToken(fosite.RefreshToken, signature, req)
 	return nil
 }
 
@@ -147,6 +232,9 @@ func (s *MemoryStore) GetRefreshTokenSession(_ context.Context, signature string
 }
 
 func (s *MemoryStore) DeleteRefreshTokenSession(_ context.Context, signature string) error {
+	if req, ok := s.RefreshTokens[signature]; ok {
+		s.unindexToken(fosite.RefreshToken, signature, req)
+	}
 	delete(s.RefreshTokens, signature)
 	return nil
 }
@@ -194,6 +282,7 @@ func (s *MemoryStore) PersistRefreshTokenGrantSession(ctx context.Context, origi
 func (s *MemoryStore) RevokeRefreshToken(ctx context.Context, requestID string) error {
 	if signature, exists := s.RefreshTokenRequestIDs[requestID]; exists {
 		s.DeleteRefreshTokenSession(ctx, signature)
+		delete(s.RefreshTokenRequestIDs, requestID)
 	}
 	return nil
 }
@@ -201,6 +290,185 @@ func (s *MemoryStore) RevokeRefreshToken(ctx context.Context, requestID string)
 func (s *MemoryStore) RevokeAccessToken(ctx context.Context, requestID string) error {
 	if signature, exists := s.AccessTokenRequestIDs[requestID]; exists {
 		s.DeleteAccessTokenSession(ctx, signature)
+		delete(s.AccessTokenRequestIDs, requestID)
+	}
+	return nil
+}
+
+func (s *MemoryStore) SetDPoPProofUsed(_ context.Context, jti string, exp time.Time) error {
+	now := time.Now()
+	for id, e := range s.DPoPProofs {
+		if e.Before(now) {
+			delete(s.DPoPProofs, id)
+		}
+	}
+
+	if _, ok := s.DPoPProofs[jti]; ok {
+		return errors.New("DPoP proof was already used")
+	}
+	s.DPoPProofs[jti] = exp
+	return nil
+}
+
+func (s *MemoryStore) indexToken(tokenType fosite.TokenType, key string, req fosite.Requester) {
+	if s.SubjectTokens == nil {
+		s.SubjectTokens = make(map[string]map[MemoryTokenRelation]bool)
+	}
+	if s.ClientTokens == nil {
+		s.ClientTokens = make(map[string]map[MemoryTokenRelation]bool)
+	}
+
+	rel := MemoryTokenRelation{TokenType: tokenType, Key: key}
+	if session := req.GetSession(); session != nil && session.GetSubject() != "" {
+		if s.SubjectTokens[session.GetSubject()] == nil {
+			s.SubjectTokens[session.GetSubject()] = make(map[MemoryTokenRelation]bool)
+		}
+		s.SubjectTokens[session.GetSubject()][rel] = true
+	}
+	if client := req.GetClient(); client != nil {
+		if s.ClientTokens[client.GetID()] == nil {
+			s.ClientTokens[client.GetID()] = make(map[MemoryTokenRelation]bool)
+		}
+		s.ClientTokens[client.GetID()][rel] = true
+	}
+}
+
+// matchingTokens returns the indexed tokens of the given types matching the filter.
+func (s *MemoryStore) matchingTokens(filter fosite.BulkRevocationFilter, tokenTypes ...fosite.TokenType) []MemoryTokenRelation {
+	var candidates map[MemoryTokenRelation]bool
+	if filter.Subject != "" {
+		candidates = s.SubjectTokens[filter.Subject]
+	} else if filter.ClientID != "" {
+		candidates = s.ClientTokens[filter.ClientID]
+	}
+
+	var matches []MemoryTokenRelation
+	for rel := range candidates {
+		if filter.Subject != "" && filter.ClientID != "" && !s.ClientTokens[filter.ClientID][rel] {
+			continue
+		}
+
+		for _, tokenType := range tokenTypes {
+			if rel.TokenType == tokenType {
+				matches = append(matches, rel)
+			}
+		}
+	}
+	return matches
+}
+
+// unindexToken removes a token from the subject and client indexes. It is called whenever a token is deleted.
+func (s *MemoryStore) unindexToken(tokenType fosite.TokenType, key string, req fosite.Requester) {
+	rel := MemoryTokenRelation{TokenType: tokenType, Key: key}
+	if session := req.GetSession(); session != nil {
+		unindexRelation(s.SubjectTokens, session.GetSubject(), rel)
+	}
+	if client := req.GetClient(); client != nil {
+		unindexRelation(s.ClientTokens, client.GetID(), rel)
+	}
+}
+
+func unindexRelation(index map[string]map[MemoryTokenRelation]bool, key string, rel MemoryTokenRelation) {
+	tokens, ok := index[key]
+	if !ok {
+		return
+	}
+	delete(tokens, rel)
+	if len(tokens) == 0 {
+		delete(index, key)
+	}
+}
+
+// RevokeTokens revokes all access tokens, refresh tokens and authorization codes matching the filter.
+func (s *MemoryStore) RevokeTokens(ctx context.Context, filter fosite.BulkRevocationFilter) error {
+	matches := s.matchingTokens(filter, fosite.AccessToken, fosite.RefreshToken, fosite.AuthorizeCode)
+	for _, rel := range matches {
+		switch rel.TokenType {
+		case fosite.AccessToken:
+			if req, ok := s.AccessTokens[rel.Key]; ok {
+				delete(s.AccessTokenRequestIDs, req.GetID())
+			}
+			s.DeleteAccessTokenSession(ctx, rel.Key)
+		case fosite.RefreshToken:
+			if req, ok := s.RefreshTokens[rel.Key]; ok {
+				delete(s.RefreshTokenRequestIDs, req.GetID())
+			}
+			s.DeleteRefreshTokenSession(ctx, rel.Key)
+		case fosite.AuthorizeCode:
+			s.DeleteAuthorizeCodeSession(ctx, rel.Key)
+		}
+	}
+	return nil
+}
+
+// DeleteOpenIDConnectSessions removes all open id connect sessions matching the filter.
+func (s *MemoryStore) DeleteOpenIDConnectSessions(ctx context.Context, filter fosite.BulkRevocationFilter) error {
+	matches := s.matchingTokens(filter, fosite.IDToken)
+	for _, rel := range matches {
+		s.DeleteOpenIDConnectSession(ctx, rel.Key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) AddLoginSessionClient(_ context.Context, sessionID string, clientID string) error {
+	if s.LoginSessionClients == nil {
+		s.LoginSessionClients = make(map[string][]string)
+	}
+
+	for _, id := range s.LoginSessionClients[sessionID] {
+		if id == clientID {
+			return nil
+		}
+	}
+	s.LoginSessionClients[sessionID] = append(s.LoginSessionClients[sessionID], clientID)
+	return nil
+}
+
+func (s *MemoryStore) GetLoginSessionClients(_ context.Context, sessionID string) ([]string, error) {
+	clients, ok := s.LoginSessionClients[sessionID]
+	if !ok {
+		return nil, fosite.ErrNotFound
+	}
+	return clients, nil
+}
+
+func (s *MemoryStore) DeleteLoginSession(_ context.Context, sessionID string) error {
+	delete(s.LoginSessionClients, sessionID)
+	return nil
+}
+
+func (s *MemoryStore) SaveConsent(_ context.Context, record *fosite.ConsentRecord) error {
+	if s.Consents == nil {
+		s.Consents = make(map[string]map[string]fosite.ConsentRecord)
+	}
+	if _, ok := s.Consents[record.Subject]; !ok {
+		s.Consents[record.Subject] = make(map[string]fosite.ConsentRecord)
+	}
+	s.Consents[record.Subject][record.ClientID] = *record
+	return nil
+}
+
+func (s *MemoryStore) GetConsent(_ context.Context, subject, clientID string) (*fosite.ConsentRecord, error) {
+	record, ok := s.Consents[subject][clientID]
+	if !ok {
+		return nil, fosite.ErrNotFound
+	}
+	return &record, nil
+}
+
+func (s *MemoryStore) GetSubjectConsents(_ context.Context, subject string) ([]*fosite.ConsentRecord, error) {
+	var records []*fosite.ConsentRecord
+	for _, record := range s.Consents[subject] {
+		r := record
+		records = append(records, &r)
+	}
+	return records, nil
+}
+
+func (s *MemoryStore) RevokeConsent(_ context.Context, subject, clientID string) error {
+	if _, ok := s.Consents[subject][clientID]; !ok {
+		return fosite.ErrNotFound
 	}
+	delete(s.Consents[subject], clientID)
 	return nil
 }
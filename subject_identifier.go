@@ -0,0 +1,203 @@
+package fosite
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SubjectTypePublic provides the same subject identifier to all clients.
+	SubjectTypePublic = "public"
+
+	// SubjectTypePairwise provides a different subject identifier to each sector, so that clients can not correlate
+	// the end-user's activities without permission.
+	SubjectTypePairwise = "pairwise"
+)
+
+// GetClientSubjectType returns the subject type of a client. Clients not implementing ClientWithSubjectType use
+// public subject identifiers.
+func GetClientSubjectType(c Client) string {
+	if sc, ok := c.(ClientWithSubjectType); ok && sc.GetSubjectType() != "" {
+		return sc.GetSubjectType()
+	}
+	return SubjectTypePublic
+}
+
+// SubjectIdentifierStrategy returns the subject identifier a client sees for an end-user. It is applied to the
+// "sub" claim of ID tokens, UserInfo responses and introspection responses.
+type SubjectIdentifierStrategy interface {
+	GetSubjectIdentifier(ctx context.Context, client Client, subject string) (string, error)
+}
+
+// SectorIdentifierFetcher fetches the JSON array of redirect URIs a sector_identifier_uri points to, see
+// https://openid.net/specs/openid-connect-registration-1_0.html#SectorIdentifierValidation
+type SectorIdentifierFetcher interface {
+	FetchSectorIdentifier(ctx context.Context, uri string) ([]string, error)
+}
+
+// DefaultSectorIdentifierFetcher fetches sector identifier URIs using HTTP GET. Implementations caching the result
+// can be used instead, because the document is fetched every time a pairwise subject identifier is calculated.
+type DefaultSectorIdentifierFetcher struct {
+	// Client is the HTTP client used to fetch the document. Defaults to a client with a ten second timeout.
+	Client *http.Client
+}
+
+func (f *DefaultSectorIdentifierFetcher) FetchSectorIdentifier(ctx context.Context, uri string) ([]string, error) {
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: time.Second * 10}
+	}
+
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Sector identifier URI %s responded with status code %d", uri, res.StatusCode)
+	}
+
+	var redirectURIs []string
+	if err := json.NewDecoder(res.Body).Decode(&redirectURIs); err != nil {
+		return nil, errors.Wrapf(err, "Sector identifier URI %s did not return a JSON array of strings", uri)
+	}
+	return redirectURIs, nil
+}
+
+// PairwiseSubjectIdentifierStrategy calculates pairwise subject identifiers for clients using
+// SubjectTypePairwise as described in https://openid.net/specs/openid-connect-core-1_0.html#PairwiseAlg
+//
+//   The Subject Identifier is calculated as a SHA-256 hash of the
+//   concatenation of the Sector Identifier, the local account ID and a
+//   salt value that is kept secret by the Provider.
+//
+// Clients using SubjectTypePublic see the subject unchanged.
+type PairwiseSubjectIdentifierStrategy struct {
+	// Salt is kept secret. Changing it changes all pairwise subject identifiers.
+	Salt []byte
+
+	// Fetcher fetches sector identifier URIs. Defaults to DefaultSectorIdentifierFetcher.
+	Fetcher SectorIdentifierFetcher
+}
+
+func (s *PairwiseSubjectIdentifierStrategy) GetSubjectIdentifier(ctx context.Context, client Client, subject string) (string, error) {
+	switch GetClientSubjectType(client) {
+	case SubjectTypePublic:
+		return subject, nil
+	case SubjectTypePairwise:
+	default:
+		return "", errors.Wrapf(ErrInvalidClient, "Subject type %s is not supported", GetClientSubjectType(client))
+	}
+
+	sector, err := s.GetSectorIdentifier(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(sector))
+	hash.Write([]byte(subject))
+	hash.Write(s.Salt)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetSectorIdentifier returns the sector identifier of a client as defined in
+// https://openid.net/specs/openid-connect-core-1_0.html#PairwiseAlg
+//
+//   The Sector Identifier is the host component of a URL used by the
+//   Relying Party's organization that is an input to the computation of
+//   pairwise Subject Identifiers for that Relying Party.
+//
+//   If the Client has not provided a value for sector_identifier_uri in
+//   Dynamic Client Registration, the Sector Identifier used for pairwise
+//   identifier calculation is the host component of the registered
+//   redirect_uri. If there are multiple hostnames in the registered
+//   redirect_uris, the Client MUST register a sector_identifier_uri.
+//
+// The sector identifier URI must use https and list all redirect URIs of the client.
+func (s *PairwiseSubjectIdentifierStrategy) GetSectorIdentifier(ctx context.Context, client Client) (string, error) {
+	var sectorIdentifierURI string
+	if sc, ok := client.(ClientWithSubjectType); ok {
+		sectorIdentifierURI = sc.GetSectorIdentifierURI()
+	}
+
+	if sectorIdentifierURI == "" {
+		var sector string
+		for _, raw := range client.GetRedirectURIs() {
+			redirectURI, err := url.Parse(raw)
+			if err != nil {
+				return "", errors.Wrap(ErrInvalidClient, err.Error())
+			} else if sector != "" && sector != redirectURI.Hostname() {
+				return "", errors.Wrap(ErrInvalidClient, "Redirect URIs with different hosts require a sector identifier URI")
+			}
+			sector = redirectURI.Hostname()
+		}
+
+		if sector == "" {
+			return "", errors.Wrap(ErrInvalidClient, "Pairwise subject identifiers require a redirect URI or a sector identifier URI")
+		}
+		return sector, nil
+	}
+
+	u, err := url.Parse(sectorIdentifierURI)
+	if err != nil {
+		return "", errors.Wrap(ErrInvalidClient, err.Error())
+	} else if u.Scheme != "https" || u.Hostname() == "" {
+		return "", errors.Wrap(ErrInvalidClient, "Sector identifier URI must use https")
+	}
+
+	fetcher := s.Fetcher
+	if fetcher == nil {
+		fetcher = &DefaultSectorIdentifierFetcher{}
+	}
+
+	allowed, err := fetcher.FetchSectorIdentifier(ctx, sectorIdentifierURI)
+	if err != nil {
+		return "", errors.Wrap(ErrServerError, err.Error())
+	}
+
+	listed := map[string]bool{}
+	for _, redirectURI := range allowed {
+		listed[redirectURI] = true
+	}
+	for _, redirectURI := range client.GetRedirectURIs() {
+		if !listed[redirectURI] {
+			return "", errors.Wrapf(ErrInvalidClient, "Redirect URI %s is not listed by the sector identifier URI", redirectURI)
+		}
+	}
+
+	return u.Hostname(), nil
+}
+
+// GetSubjectIdentifier applies the strategy to the subject, or returns the subject unchanged if the strategy is nil.
+func GetSubjectIdentifier(ctx context.Context, strategy SubjectIdentifierStrategy, client Client, subject string) (string, error) {
+	if strategy == nil || client == nil || subject == "" {
+		return subject, nil
+	}
+	return strategy.GetSubjectIdentifier(ctx, client, subject)
+}
+
+// IntrospectionSubjectResponder is an optional extension of IntrospectionResponder for responses whose "sub" member
+// differs from the session's subject, see Fosite.SubjectIdentifierStrategy. IntrospectionResponse implements it.
+type IntrospectionSubjectResponder interface {
+	// GetSubject returns the subject identifier of the introspected token.
+	GetSubject() string
+
+	IntrospectionResponder
+}
@@ -0,0 +1,112 @@
+package fosite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticSectorIdentifierFetcher map[string][]string
+
+func (f staticSectorIdentifierFetcher) FetchSectorIdentifier(_ context.Context, uri string) ([]string, error) {
+	if uris, ok := f[uri]; ok {
+		return uris, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestPairwiseSubjectIdentifierStrategy(t *testing.T) {
+	s := &PairwiseSubjectIdentifierStrategy{
+		Salt: []byte("salt"),
+		Fetcher: staticSectorIdentifierFetcher{
+			"https://example.com/sector.json": {"https://a.example.com/cb", "https://b.example.com/cb"},
+		},
+	}
+
+	public, err := s.GetSubjectIdentifier(nil, &DefaultClient{ID: "public"}, "peter")
+	require.Nil(t, err)
+	assert.Equal(t, "peter", public)
+
+	a, err := s.GetSubjectIdentifier(nil, &DefaultClient{SubjectType: SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb", "https://a.example.com:8443/other"}}, "peter")
+	require.Nil(t, err)
+	assert.NotEqual(t, "peter", a)
+	assert.Len(t, a, 64)
+
+	// Clients of the same sector see the same subject identifier.
+	a2, err := s.GetSubjectIdentifier(nil, &DefaultClient{SubjectType: SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb2"}}, "peter")
+	require.Nil(t, err)
+	assert.Equal(t, a, a2)
+
+	other, err := s.GetSubjectIdentifier(nil, &DefaultClient{SubjectType: SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb"}}, "bob")
+	require.Nil(t, err)
+	assert.NotEqual(t, a, other)
+
+	sector, err := s.GetSubjectIdentifier(nil, &DefaultClient{SubjectType: SubjectTypePairwise, SectorIdentifierURI: "https://example.com/sector.json", RedirectURIs: []string{"https://a.example.com/cb", "https://b.example.com/cb"}}, "peter")
+	require.Nil(t, err)
+	assert.NotEqual(t, a, sector)
+
+	for k, c := range []struct {
+		description string
+		client      Client
+		expectErr   error
+	}{
+		{
+			description: "should fail because the redirect URIs have different hosts",
+			client:      &DefaultClient{SubjectType: SubjectTypePairwise, RedirectURIs: []string{"https://a.example.com/cb", "https://b.example.com/cb"}},
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail because there are no redirect URIs",
+			client:      &DefaultClient{SubjectType: SubjectTypePairwise},
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail because the sector identifier URI does not use https",
+			client:      &DefaultClient{SubjectType: SubjectTypePairwise, SectorIdentifierURI: "http://example.com/sector.json", RedirectURIs: []string{"https://a.example.com/cb"}},
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail because a redirect URI is not listed",
+			client:      &DefaultClient{SubjectType: SubjectTypePairwise, SectorIdentifierURI: "https://example.com/sector.json", RedirectURIs: []string{"https://c.example.com/cb"}},
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail because the sector identifier URI can not be fetched",
+			client:      &DefaultClient{SubjectType: SubjectTypePairwise, SectorIdentifierURI: "https://example.com/unknown.json", RedirectURIs: []string{"https://a.example.com/cb"}},
+			expectErr:   ErrServerError,
+		},
+		{
+			description: "should fail because the subject type is unknown",
+			client:      &DefaultClient{SubjectType: "foo", RedirectURIs: []string{"https://a.example.com/cb"}},
+			expectErr:   ErrInvalidClient,
+		},
+	} {
+		_, err := s.GetSubjectIdentifier(nil, c.client, "peter")
+		assert.True(t, errors.Cause(err) == c.expectErr, "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+}
+
+func TestDefaultSectorIdentifierFetcher(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sector.json" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(rw).Encode([]string{"https://a.example.com/cb"})
+	}))
+	defer ts.Close()
+
+	f := &DefaultSectorIdentifierFetcher{Client: ts.Client()}
+	uris, err := f.FetchSectorIdentifier(nil, ts.URL+"/sector.json")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"https://a.example.com/cb"}, uris)
+
+	_, err = f.FetchSectorIdentifier(nil, ts.URL+"/unknown.json")
+	assert.NotNil(t, err)
+}
@@ -17,6 +17,7 @@ type IDTokenClaims struct {
 	AuthTime        time.Time
 	AccessTokenHash string
 	CodeHash        string
+	SessionID       string
 	Extra           map[string]interface{}
 }
 
@@ -36,6 +37,11 @@ func (c *IDTokenClaims) ToMap() map[string]interface{} {
 		ret["c_hash"] = c.CodeHash
 	}
 
+	// https://openid.net/specs/openid-connect-frontchannel-1_0.html#ClaimsContents
+	if len(c.SessionID) > 0 {
+		ret["sid"] = c.SessionID
+	}
+
 	if !c.AuthTime.IsZero() {
 		ret["auth_time"] = c.AuthTime.Unix()
 	}
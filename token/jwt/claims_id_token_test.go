@@ -17,6 +17,7 @@ var idTokenClaims = &IDTokenClaims{
 	AuthTime:        time.Now(),
 	AccessTokenHash: "foobar",
 	CodeHash:        "barfoo",
+	SessionID:       "session",
 	Extra: map[string]interface{}{
 		"foo": "bar",
 		"baz": "bar",
@@ -42,6 +43,7 @@ func TestIDTokenClaimsToMap(t *testing.T) {
 		"baz":       idTokenClaims.Extra["baz"],
 		"at_hash":   idTokenClaims.AccessTokenHash,
 		"c_hash":    idTokenClaims.CodeHash,
+		"sid":       idTokenClaims.SessionID,
 		"auth_time": idTokenClaims.AuthTime.Unix(),
 	}, idTokenClaims.ToMap())
 }
@@ -11,7 +11,7 @@ import (
 type JWTClaims struct {
 	Subject   string
 	Issuer    string
-	Audience  string
+	Audience  []string
 	JTI       string
 	IssuedAt  time.Time
 	NotBefore time.Time
@@ -31,7 +31,9 @@ func (c *JWTClaims) ToMap() map[string]interface{} {
 
 	ret["sub"] = c.Subject
 	ret["iss"] = c.Issuer
-	ret["aud"] = c.Audience
+	if len(c.Audience) > 0 {
+		ret["aud"] = c.Audience
+	}
 
 	if !c.IssuedAt.IsZero() {
 		ret["iat"] = float64(c.IssuedAt.Unix()) // jwt-go does not support int64 as datatype
@@ -68,8 +70,18 @@ func (c *JWTClaims) FromMap(m map[string]interface{}) {
 				c.Issuer = s
 			}
 		case "aud":
-			if s, ok := v.(string); ok {
-				c.Audience = s
+			switch v.(type) {
+			case string:
+				c.Audience = []string{v.(string)}
+			case []string:
+				c.Audience = v.([]string)
+			case []interface{}:
+				c.Audience = make([]string, len(v.([]interface{})))
+				for i, vi := range v.([]interface{}) {
+					if s, ok := vi.(string); ok {
+						c.Audience[i] = s
+					}
+				}
 			}
 		case "iat":
 			switch v.(type) {
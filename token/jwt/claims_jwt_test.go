@@ -13,7 +13,7 @@ var jwtClaims = &JWTClaims{
 	IssuedAt:  time.Now().Round(time.Second),
 	Issuer:    "fosite",
 	NotBefore: time.Now().Round(time.Second),
-	Audience:  "tests",
+	Audience:  []string{"tests"},
 	ExpiresAt: time.Now().Add(time.Hour).Round(time.Second),
 	JTI:       "abcdef",
 	Scope:     []string{"email", "offline"},
@@ -67,3 +67,14 @@ func TestClaimsFromMap(t *testing.T) {
 	claims.FromMap(jwtClaimsMap)
 	assert.Equal(t, jwtClaims, &claims)
 }
+
+func TestClaimsFromMapWithAudience(t *testing.T) {
+	var claims JWTClaims
+	claims.FromMap(map[string]interface{}{"aud": "tests"})
+	assert.Equal(t, []string{"tests"}, claims.Audience)
+
+	claims.FromMap(map[string]interface{}{"aud": []interface{}{"foo", "bar"}})
+	assert.Equal(t, []string{"foo", "bar"}, claims.Audience)
+
+	assert.NotContains(t, (&JWTClaims{}).ToMap(), "aud")
+}
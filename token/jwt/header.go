@@ -7,9 +7,10 @@ type Headers struct {
 	Extra map[string]interface{}
 }
 
-// ToMap will transform the headers to a map structure
+// ToMap will transform the headers to a map structure. The alg header is determined by the signing method and
+// therefore filtered.
 func (h *Headers) ToMap() map[string]interface{} {
-	var filter = map[string]bool{"alg": true, "typ": true}
+	var filter = map[string]bool{"alg": true}
 	var extra = map[string]interface{}{}
 
 	// filter known values from extra.
@@ -14,3 +14,12 @@ func TestHeaderToMap(t *testing.T) {
 		"foo": "bar",
 	}, header.ToMap())
 }
+
+func TestHeaderToMapFiltersAlg(t *testing.T) {
+	header := &Headers{}
+	header.Add("alg", "none")
+	header.Add("typ", "at+jwt")
+	assert.Equal(t, map[string]interface{}{
+		"typ": "at+jwt",
+	}, header.ToMap())
+}
@@ -24,7 +24,13 @@ func (j *RS256JWTStrategy) Generate(claims jwt.Claims, header Mapper) (string, s
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header = assign(token.Header, header.ToMap())
+	headers := header.ToMap()
+	token.Header = assign(token.Header, headers)
+
+	// The header may override the default type, e.g. with at+jwt for access tokens.
+	if typ, ok := headers["typ"]; ok {
+		token.Header["typ"] = typ
+	}
 
 	var sig, sstr string
 	var err error